@@ -0,0 +1,102 @@
+package https
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//set Cache-Control: max-age and Expires.
+func (p *Context) SetCacheControl(maxAge time.Duration) {
+	p.SetHeader(headerTypeCacheControl, "max-age="+strconv.FormatInt(int64(maxAge/time.Second), 10))
+	p.SetHeader(headerTypeExpires, time.Now().Add(maxAge).UTC().Format(http.TimeFormat))
+}
+
+//disable the cache of the client and the proxies.
+func (p *Context) NoCache() {
+	p.SetHeader(headerTypeCacheControl, "no-store, no-cache, must-revalidate")
+	p.SetHeader(headerTypePragma, "no-cache")
+	p.SetHeader(headerTypeExpires, "0")
+}
+
+//set ETag by hash of data, write 304 instead of data if it matches If-None-Match.
+func (p *Context) WriteWithETag(data []byte) error {
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	p.SetHeader(headerTypeETag, etag)
+	if etagMatch(p.GetHeader(headerTypeIfNoneMatch), etag) {
+		h := p.W.Header()
+		h.Del(headerTypeContentType)
+		h.Del(headerTypeContentLength)
+		p.W.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	_, err := p.W.Write(data)
+
+	return p.fail(err)
+}
+
+func (p *Context) WriteJSONWithETag(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return p.fail(err)
+	}
+
+	var buf bytes.Buffer
+	escapeJSON(&buf, data)
+	p.SetHeader(headerTypeContentType, headerTypeContentJSON)
+
+	return p.WriteWithETag(buf.Bytes())
+}
+
+//set Last-Modified, write 304 and return true if the content isn't modified since If-Modified-Since.
+//If-Modified-Since is ignored if If-None-Match is present, or the method isn't GET or HEAD.
+func (p *Context) CheckNotModified(modtime time.Time) bool {
+	if modtime.IsZero() || modtime.Equal(time.Unix(0, 0)) {
+		return false
+	}
+	modtime = modtime.Truncate(time.Second)
+	p.SetHeader(headerTypeLastModified, modtime.UTC().Format(http.TimeFormat))
+
+	if p.R.Method != http.MethodGet && p.R.Method != http.MethodHead || p.GetHeader(headerTypeIfNoneMatch) != "" {
+		return false
+	}
+	since, err := http.ParseTime(p.GetHeader(headerTypeIfModifiedSince))
+	if err != nil || modtime.After(since) {
+		return false
+	}
+
+	h := p.W.Header()
+	h.Del(headerTypeContentType)
+	h.Del(headerTypeContentLength)
+	p.W.WriteHeader(http.StatusNotModified)
+
+	return true
+}
+
+//weak comparison is used for If-None-Match.
+func etagMatch(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	headerTypeExpires         = "Expires"
+	headerTypePragma          = "Pragma"
+	headerTypeETag            = "ETag"
+	headerTypeIfNoneMatch     = "If-None-Match"
+	headerTypeLastModified    = "Last-Modified"
+	headerTypeIfModifiedSince = "If-Modified-Since"
+)
@@ -0,0 +1,209 @@
+package https
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+//compress the response by gzip if the client accepts it.
+//the response smaller than MinCompress, the HEAD request and the status without body are not compressed.
+//IMPORTANT: Close must be called when the handler returns, otherwise the response smaller than MinCompress
+//is never sent. ToHTTPHandler calls it, the user of NewContext or AcquireContext should defer p.Close().
+func (p *Context) EnableCompression() {
+	if _, ok := p.W.(*gzipWriter); ok || p.R.Method == http.MethodHead {
+		return
+	}
+	if !acceptGzip(p.GetHeader(headerTypeAcceptEncoding)) {
+		return
+	}
+
+	p.W.Header().Add(headerTypeVary, headerTypeAcceptEncoding)
+	p.W = &gzipWriter{ResponseWriter: p.W, min: p.MinCompress}
+}
+
+//gzip;q=0 refuses gzip, * is used only if gzip isn't listed.
+func acceptGzip(header string) bool {
+	gz, star := -1.0, -1.0
+	for _, item := range parseAccept(header) {
+		if strings.EqualFold(item.value, "gzip") || strings.EqualFold(item.value, "x-gzip") {
+			if gz < 0 {
+				gz = item.q
+			}
+		} else if item.value == "*" && star < 0 {
+			star = item.q
+		}
+	}
+	if gz >= 0 {
+		return gz > 0
+	}
+
+	return star > 0
+}
+
+//gzipWriter buffers the data until it's larger than min, then decides whether to compress.
+type gzipWriter struct {
+	http.ResponseWriter
+	gz     *gzip.Writer
+	buf    []byte
+	min    int
+	code   int
+	direct bool //not compressed, write to ResponseWriter directly
+}
+
+func (p *gzipWriter) WriteHeader(code int) {
+	if p.code == 0 {
+		p.code = code
+	}
+}
+
+func (p *gzipWriter) Write(data []byte) (int, error) {
+	if p.gz != nil {
+		return p.gz.Write(data)
+	} else if p.direct {
+		return p.ResponseWriter.Write(data)
+	}
+
+	p.buf = append(p.buf, data...)
+	if len(p.buf) >= p.min {
+		if err := p.start(true); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+func (p *gzipWriter) Flush() {
+	if p.gz == nil && !p.direct {
+		p.start(true)
+	}
+	if p.gz != nil {
+		p.gz.Flush()
+	}
+	if f, ok := p.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (p *gzipWriter) Unwrap() http.ResponseWriter {
+	return p.ResponseWriter
+}
+
+func (p *gzipWriter) Close() error {
+	if p.gz == nil && !p.direct {
+		if err := p.start(false); err != nil {
+			return err
+		}
+	}
+	if p.gz != nil {
+		return p.gz.Close()
+	}
+
+	return nil
+}
+
+//write the header and the buffered data.
+//nothing is compressed if the buffer is empty or the status doesn't allow body.
+func (p *gzipWriter) start(compress bool) error {
+	h := p.Header()
+	if len(p.buf) == 0 || !bodyAllowed(p.code) || h.Get(headerTypeContentEncoding) != "" {
+		compress = false
+	}
+	if compress {
+		h.Set(headerTypeContentEncoding, "gzip")
+		h.Del(headerTypeContentLength)
+	}
+	if p.code != 0 {
+		p.ResponseWriter.WriteHeader(p.code)
+	}
+
+	buf := p.buf
+	p.buf = nil
+	if compress {
+		p.gz = gzip.NewWriter(p.ResponseWriter)
+		_, err := p.gz.Write(buf)
+		return err
+	}
+
+	p.direct = true
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := p.ResponseWriter.Write(buf)
+
+	return err
+}
+
+//1xx, 204 and 304 don't have body, 0 means 200.
+func bodyAllowed(code int) bool {
+	return code == 0 || code >= 200 && code != http.StatusNoContent && code != http.StatusNotModified
+}
+
+//decodeReader decompresses the request body of gzip or deflate.
+type decodeReader struct {
+	r        io.Reader
+	encoding string
+	max      int64 //max decompressed size, 0 means unlimited
+	n        int64
+	err      error
+}
+
+func newDecodeReader(r io.Reader, encoding string, max int64) io.Reader {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	if encoding == "identity" {
+		return r
+	}
+
+	return &decodeReader{r: r, encoding: encoding, max: max}
+}
+
+func (p *decodeReader) Read(data []byte) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	} else if p.encoding != "" {
+		p.init()
+		if p.err != nil {
+			return 0, p.err
+		}
+	}
+
+	n, err := p.r.Read(data)
+	p.n += int64(n)
+	if p.max > 0 && p.n > p.max {
+		n -= int(p.n - p.max)
+		p.n = p.max
+		err = errBodyTooLarge
+	}
+	if err != nil {
+		p.err = err
+	}
+
+	return n, err
+}
+
+//create the decompressor lazily, since it reads the header of the body.
+func (p *decodeReader) init() {
+	encoding := p.encoding
+	p.encoding = ""
+	switch encoding {
+	case "gzip", "x-gzip":
+		p.r, p.err = gzip.NewReader(p.r)
+	case "deflate":
+		p.r, p.err = zlib.NewReader(p.r)
+	default:
+		p.err = errContentEncoding
+	}
+}
+
+var errContentEncoding = errors.New("Unsupported content encoding")
+
+const (
+	headerTypeAcceptEncoding  = "Accept-Encoding"
+	headerTypeContentEncoding = "Content-Encoding"
+	headerTypeContentLength   = "Content-Length"
+	headerTypeVary            = "Vary"
+)
@@ -0,0 +1,160 @@
+package https
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newCompressContext(method, accept string) (*Context, *httptest.ResponseRecorder) {
+	r := httptest.NewRequest(method, "/", nil)
+	r.Header.Set(headerTypeAcceptEncoding, accept)
+	w := httptest.NewRecorder()
+	p := NewContext(w, r)
+	p.EnableCompression()
+
+	return p, w
+}
+
+func TestCompression(t *testing.T) {
+	large := strings.Repeat("x", 0x800)
+	tests := []struct {
+		method string
+		accept string
+		body   string
+		gzip   bool
+	}{
+		{http.MethodGet, "gzip", large, true},
+		{http.MethodGet, "gzip", "small", false},
+		{http.MethodGet, "gzip;q=0", large, false},
+		{http.MethodGet, "gzip;q=0, *", large, false},
+		{http.MethodGet, "*", large, true},
+		{http.MethodHead, "gzip", large, false},
+	}
+	for _, tt := range tests {
+		p, w := newCompressContext(tt.method, tt.accept)
+		p.WriteRaw(tt.body)
+		if err := p.Close(); err != nil {
+			t.Fatalf("%s %q: Close = %v", tt.method, tt.accept, err)
+		}
+
+		encoded := w.Header().Get(headerTypeContentEncoding) == "gzip"
+		if encoded != tt.gzip {
+			t.Errorf("%s %q %d bytes: gzip = %v, want %v", tt.method, tt.accept, len(tt.body), encoded, tt.gzip)
+			continue
+		}
+		body := w.Body.String()
+		if encoded {
+			gz, err := gzip.NewReader(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := ioutil.ReadAll(gz)
+			body = string(data)
+		}
+		if body != tt.body {
+			t.Errorf("%s %q: body of %d bytes, want %d", tt.method, tt.accept, len(body), len(tt.body))
+		}
+	}
+}
+
+func TestCompressionNoBody(t *testing.T) {
+	p, w := newCompressContext(http.MethodGet, "gzip")
+	p.WriteStatus(http.StatusNoContent)
+	p.Flush()
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusNoContent || w.Header().Get(headerTypeContentEncoding) != "" || w.Body.Len() != 0 {
+		t.Errorf("code = %d, Content-Encoding = %q, body = %d bytes", w.Code, w.Header().Get(headerTypeContentEncoding), w.Body.Len())
+	}
+}
+
+func gzipData(data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	gz.Close()
+
+	return buf.Bytes()
+}
+
+func newEncodedContext(encoding string, body []byte) *Context {
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	r.Header.Set(headerTypeContentEncoding, encoding)
+
+	return NewContext(httptest.NewRecorder(), r)
+}
+
+func TestDecodeBody(t *testing.T) {
+	var deflate bytes.Buffer
+	zw := zlib.NewWriter(&deflate)
+	zw.Write([]byte("hello"))
+	zw.Close()
+
+	tests := []struct {
+		encoding string
+		body     []byte
+	}{
+		{"gzip", gzipData([]byte("hello"))},
+		{"deflate", deflate.Bytes()},
+		{"identity", []byte("hello")},
+	}
+	for _, tt := range tests {
+		p := newEncodedContext(tt.encoding, tt.body)
+		if err := p.BufferBody(); err != nil {
+			t.Fatalf("%s: BufferBody = %v", tt.encoding, err)
+		}
+
+		if data, _ := p.GetBody(); string(data) != "hello" {
+			t.Errorf("%s: GetBody = %q", tt.encoding, data)
+		}
+		if p.R.ContentLength != 5 || p.R.Header.Get(headerTypeContentLength) != "5" || p.R.Header.Get(headerTypeContentEncoding) != "" {
+			t.Errorf("%s: ContentLength = %d, Content-Encoding = %q", tt.encoding, p.R.ContentLength, p.R.Header.Get(headerTypeContentEncoding))
+		}
+		if data, _ := ioutil.ReadAll(p.R.Body); string(data) != "hello" {
+			t.Errorf("%s: R.Body = %q", tt.encoding, data)
+		}
+	}
+
+	if err := newEncodedContext("br", []byte("hello")).BufferBody(); err != errContentEncoding {
+		t.Errorf("BufferBody of br = %v, want errContentEncoding", err)
+	}
+}
+
+func TestDecodeBodyBomb(t *testing.T) {
+	p := newEncodedContext("gzip", gzipData(make([]byte, 0x100000)))
+	p.MaxBody = 0x1000
+	if err := p.BufferBody(); err != errBodyTooLarge {
+		t.Errorf("BufferBody = %v, want errBodyTooLarge", err)
+	}
+}
+
+func TestProxyBufferedGzipBody(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		w.Write([]byte(r.Header.Get(headerTypeContentEncoding) + ":" + string(data)))
+	}))
+	defer backend.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipData([]byte("hello"))))
+	r.Header.Set(headerTypeContentEncoding, "gzip")
+	w := httptest.NewRecorder()
+	p := NewContext(w, r)
+	if err := p.BufferBody(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Proxy(backend.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusOK || w.Body.String() != ":hello" {
+		t.Errorf("code = %d, body = %q", w.Code, w.Body.String())
+	}
+}
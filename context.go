@@ -2,27 +2,174 @@ package https
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"github.com/gorilla/schema"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
 	"html"
 	"io"
+	"io/fs"
 	"io/ioutil"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Context struct {
-	W      http.ResponseWriter
-	R      *http.Request
-	MaxMem int64 //upload file memory size
+	W           http.ResponseWriter
+	R           *http.Request
+	MaxMem      int64  //upload file memory size
+	MaxBody     int64  //max request body size, 0 means unlimited
+	MaxUpload   int64  //max multipart request size, 0 means unlimited
+	MinCompress int    //min response size to be compressed
+	body        []byte //buffered request body
+	bodyBuffer  *bytes.Buffer
+	sseID       uint64 //id of the last server-sent event
+	values      map[string]interface{}
+	csrfToken   string
+	session     *Session
+	aborted     bool
+	formParsed  bool
+	formErr     error
+	requestID   string
+	rw          responseWriter
 }
 
 func NewContext(w http.ResponseWriter, r *http.Request) *Context {
-	return &Context{W: w, R: r, MaxMem: 0x4000000}
+	p := &Context{}
+	p.init(w, r)
+
+	return p
+}
+
+//set the default MaxMem of the new Context.
+func SetDefaultMaxMem(n int64) {
+	defaultMaxMem = n
+}
+
+func (p *Context) init(w http.ResponseWriter, r *http.Request) {
+	*p = Context{R: r, MaxMem: defaultMaxMem, MaxBody: 0xa00000, MinCompress: 0x400}
+	p.rw.ResponseWriter = w
+	p.W = &p.rw
+}
+
+//set request-scoped value
+func (p *Context) Set(key string, value interface{}) {
+	if p.values == nil {
+		p.values = make(map[string]interface{})
+	}
+	p.values[key] = value
+}
+
+func (p *Context) Get(key string) (value interface{}, ok bool) {
+	value, ok = p.values[key]
+
+	return
+}
+
+//panic if key does not exist
+func (p *Context) MustGet(key string) interface{} {
+	if value, ok := p.values[key]; ok {
+		return value
+	}
+
+	panic("key \"" + key + "\" does not exist")
+}
+
+//return X-Request-ID of the request, a new UUID is generated if it's absent.
+//the ID is set to the response header too.
+func (p *Context) RequestID() string {
+	if p.requestID == "" {
+		if p.requestID = p.GetHeader(headerTypeRequestID); p.requestID == "" {
+			p.requestID = newUUID()
+		}
+		p.SetHeader(headerTypeRequestID, p.requestID)
+	}
+
+	return p.requestID
+}
+
+//generate UUID version 4
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (p *Context) RequestContext() context.Context {
+	return p.R.Context()
+}
+
+//Deadline, Done, Err and Value implement context.Context by the context of the request,
+//so that Context can be passed to the calls which should be canceled with the request.
+func (p *Context) Deadline() (deadline time.Time, ok bool) {
+	return p.R.Context().Deadline()
+}
+
+func (p *Context) Done() <-chan struct{} {
+	return p.R.Context().Done()
+}
+
+func (p *Context) Err() error {
+	return p.R.Context().Err()
+}
+
+//string key is found in the values set by Set first.
+func (p *Context) Value(key interface{}) interface{} {
+	if k, ok := key.(string); ok {
+		if value, ok := p.values[k]; ok {
+			return value
+		}
+	}
+
+	return p.R.Context().Value(key)
+}
+
+func (p *Context) IsGet() bool {
+	return p.R.Method == http.MethodGet
+}
+
+func (p *Context) IsPost() bool {
+	return p.R.Method == http.MethodPost
+}
+
+func (p *Context) IsPut() bool {
+	return p.R.Method == http.MethodPut
+}
+
+func (p *Context) IsDelete() bool {
+	return p.R.Method == http.MethodDelete
+}
+
+//X-Requested-With is XMLHttpRequest
+func (p *Context) IsAjax() bool {
+	return p.GetHeader(headerTypeRequestedWith) == "XMLHttpRequest"
+}
+
+func (p *Context) IsWebSocket() bool {
+	return strings.EqualFold(p.GetHeader(headerTypeUpgrade), "websocket") &&
+		strings.Contains(strings.ToLower(p.GetHeader(headerTypeConnection)), "upgrade")
 }
 
 func (p *Context) NotFound() {
@@ -35,42 +182,348 @@ func (p *Context) Error(code int) {
 	http.Error(p.W, msg, code)
 }
 
+//write the plain text message with code, use it when the text of Error isn't wanted.
+func (p *Context) ErrorText(code int, message string) {
+	http.Error(p.W, message, code)
+}
+
 func (p *Context) ServeFile(name string) {
 	http.ServeFile(p.W, p.R, name)
 }
 
+//code should be 3xx, example: http.StatusFound
+func (p *Context) Redirect(target string, code int) error {
+	return p.Render(Redirect{Request: p.R, Location: target, Code: code})
+}
+
+func (p *Context) RedirectPermanent(target string) {
+	p.Render(Redirect{Request: p.R, Location: target, Code: http.StatusMovedPermanently})
+}
+
+func (p *Context) RedirectTemporary(target string) {
+	p.Render(Redirect{Request: p.R, Location: target, Code: http.StatusFound})
+}
+
+//write the file with Content-Type by the extension, the content is sniffed if the extension is unknown.
+func (p *Context) WriteFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return p.fail(err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return p.fail(err)
+	} else if info.IsDir() {
+		return p.fail(errIsDir)
+	}
+
+	var reader io.Reader = file
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		head := make([]byte, 512)
+		n, err := io.ReadFull(file, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return p.fail(err)
+		}
+		contentType = http.DetectContentType(head[:n])
+		reader = io.MultiReader(bytes.NewReader(head[:n]), file)
+	}
+	p.SetHeader(headerTypeContentType, contentType)
+	if info.Mode().IsRegular() {
+		p.SetHeader(headerTypeContentLength, strconv.FormatInt(info.Size(), 10))
+	}
+	_, err = io.Copy(p.W, reader)
+
+	return p.fail(err)
+}
+
+//serve the file name of fsys, example: embed.FS. 404 is written if the file does not exist.
+func (p *Context) ServeFS(fsys fs.FS, name string) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+
+	file, err := fsys.Open(name)
+	if err != nil {
+		p.fsError(err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		p.fsError(err)
+		return
+	} else if info.IsDir() {
+		p.NotFound()
+		return
+	}
+
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			p.fsError(err)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+	p.ServeContent(info.Name(), info.ModTime(), content)
+}
+
+func (p *Context) fsError(err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		p.NotFound()
+	} else if errors.Is(err, fs.ErrPermission) {
+		p.Error(http.StatusForbidden)
+	} else {
+		p.Error(http.StatusInternalServerError)
+	}
+}
+
+//serve content with Range, If-Modified-Since and ETag handling, see http.ServeContent.
+func (p *Context) ServeContent(name string, modtime time.Time, content io.ReadSeeker) {
+	http.ServeContent(p.W, p.R, name, modtime, content)
+}
+
+//serve the file name as an attachment named filename.
+func (p *Context) Attachment(name, filename string) {
+	p.SetContentDisposition("attachment", filename)
+	p.ServeFile(name)
+}
+
+//write data as an attachment named filename.
+func (p *Context) Download(filename string, contentType string, data []byte) error {
+	h := p.W.Header()
+	h.Set(headerTypeContentDisposition, contentDisposition("attachment", filename))
+	if contentType != "" {
+		h.Set(headerTypeContentType, contentType)
+	}
+	h.Set(headerTypeContentLength, strconv.Itoa(len(data)))
+	_, err := p.W.Write(data)
+
+	return p.fail(err)
+}
+
+//copy the content of reader to the response.
+func (p *Context) WriteStream(reader io.Reader, contentType string) error {
+	if contentType != "" {
+		p.SetHeader(headerTypeContentType, contentType)
+	}
+	_, err := io.Copy(p.W, reader)
+
+	return p.fail(err)
+}
+
+//non-ASCII filename is encoded by RFC 5987.
+func contentDisposition(disposition, filename string) string {
+	var buf, plain strings.Builder
+	isASCII := true
+	for _, c := range filename {
+		if c < 0x20 || c >= 0x7f {
+			isASCII = false
+			plain.WriteByte('_')
+			continue
+		} else if c == '"' || c == '\\' {
+			plain.WriteByte('\\')
+		}
+		plain.WriteRune(c)
+	}
+
+	buf.WriteString(disposition)
+	buf.WriteString(`; filename="`)
+	buf.WriteString(plain.String())
+	buf.WriteByte('"')
+	if !isASCII {
+		buf.WriteString("; filename*=UTF-8''")
+		for _, b := range []byte(filename) {
+			if b < 0x80 && (b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || strings.IndexByte("!#$&+-.^_`|~", b) >= 0) {
+				buf.WriteByte(b)
+			} else {
+				fmt.Fprintf(&buf, "%%%02X", b)
+			}
+		}
+	}
+
+	return buf.String()
+}
+
 func (p *Context) FormValue(name string) string {
+	p.parseForm()
+
 	return p.R.FormValue(name)
 }
 
+//return def when the key is missing or the value is not an integer
+func (p *Context) FormInt(name string, def int) int {
+	if v, err := strconv.Atoi(p.FormValue(name)); err == nil {
+		return v
+	}
+
+	return def
+}
+
+func (p *Context) FormInt64(name string, def int64) int64 {
+	if v, err := strconv.ParseInt(p.FormValue(name), 10, 64); err == nil {
+		return v
+	}
+
+	return def
+}
+
+func (p *Context) FormFloat64(name string, def float64) float64 {
+	if v, err := strconv.ParseFloat(p.FormValue(name), 64); err == nil {
+		return v
+	}
+
+	return def
+}
+
+//accept the values of strconv.ParseBool
+func (p *Context) FormBool(name string, def bool) bool {
+	if v, err := strconv.ParseBool(p.FormValue(name)); err == nil {
+		return v
+	}
+
+	return def
+}
+
 func (p *Context) Query() url.Values {
 	return p.R.URL.Query()
 }
 
+//return true if the key exists, even the value is empty, example: ?verbose
+func (p *Context) HasQuery(name string) bool {
+	_, ok := p.Query()[name]
+
+	return ok
+}
+
+func (p *Context) HasForm(name string) bool {
+	_, ok := p.Form()[name]
+
+	return ok
+}
+
+//return def when the key is missing
+func (p *Context) QueryDefault(name, def string) string {
+	if values, ok := p.Query()[name]; ok && len(values) > 0 {
+		return values[0]
+	}
+
+	return def
+}
+
+//return def when the key is missing or the value is not an integer
+func (p *Context) QueryInt(name string, def int) int {
+	if v, err := strconv.Atoi(p.Query().Get(name)); err == nil {
+		return v
+	}
+
+	return def
+}
+
+func (p *Context) QueryInt64(name string, def int64) int64 {
+	if v, err := strconv.ParseInt(p.Query().Get(name), 10, 64); err == nil {
+		return v
+	}
+
+	return def
+}
+
+func (p *Context) QueryFloat64(name string, def float64) float64 {
+	if v, err := strconv.ParseFloat(p.Query().Get(name), 64); err == nil {
+		return v
+	}
+
+	return def
+}
+
+//accept the values of strconv.ParseBool
+func (p *Context) QueryBool(name string, def bool) bool {
+	if v, err := strconv.ParseBool(p.Query().Get(name)); err == nil {
+		return v
+	}
+
+	return def
+}
+
 func (p *Context) Form() url.Values {
-	p.R.ParseForm()
+	p.parseForm()
 
 	return p.R.Form
 }
 
 func (p *Context) PostForm() url.Values {
-	p.R.ParseForm()
+	p.parseForm()
 
 	return p.R.PostForm
 }
 
+//the multipart form is parsed by ParseMultipart, so that it's parsed once with MaxMem and MaxUpload.
+func (p *Context) parseForm() {
+	if p.Is(mimeMultipartForm) {
+		p.ParseMultipart()
+	} else {
+		p.R.ParseForm()
+	}
+}
+
 // ReadForm binds the formObject  with the form data
 // it supports any kind of type, including custom structs.
-// It will return nothing if request data are empty.
+// Nothing is decoded if request data are empty, but data is still validated.
 // The struct field tag is "form".
+// The bracket keys like items[0][name] are supported.
+// The unknown keys like _csrf are ignored, see SetFormDecoderOptions.
+// data is validated after decoding, see Validator.
 //
 func (p *Context) ReadForm(data interface{}) error {
-	values := p.Form()
-	if len(values) == 0 {
-		return nil
+	var err error
+	if values := p.Form(); len(values) > 0 {
+		err = decoderForm.Decode(data, normalizeValues(values))
+	}
+	if err == nil {
+		err = validate(data)
+	}
+
+	return p.fail(err)
+}
+
+//return Content-Type of the request without parameters, example: application/json.
+func (p *Context) ContentType() string {
+	contentType, _, err := mime.ParseMediaType(p.GetHeader(headerTypeContentType))
+	if err != nil {
+		return ""
 	}
 
-	return decoderForm.Decode(data, values)
+	return contentType
+}
+
+//compare mimeType with ContentType case-insensitively.
+func (p *Context) Is(mimeType string) bool {
+	return strings.EqualFold(p.ContentType(), mimeType)
+}
+
+//bind data by Content-Type of the request, JSON, XML and form are supported.
+func (p *Context) Bind(data interface{}) error {
+	switch p.ContentType() {
+	case mimeJSON:
+		return p.ReadJSON(data)
+	case mimeXML, mimeTextXML:
+		return p.ReadXML(data)
+	case mimeMultipartForm:
+		if err := p.ParseMultipart(); err != nil {
+			return p.fail(err)
+		}
+		return p.ReadForm(data)
+	case mimeURLEncodedForm:
+		return p.ReadForm(data)
+	default:
+		return p.fail(errUnknownDataType)
+	}
 }
 
 // ReadQuery binds the "ptr" with the url query string. The struct field tag is "url".
@@ -80,7 +533,18 @@ func (p *Context) ReadQuery(data interface{}) error {
 		return nil
 	}
 
-	return decoderQuery.Decode(data, values)
+	return p.fail(decoderQuery.Decode(data, values))
+}
+
+// ReadQueryCSV is the same as ReadQuery, but the values of fields are split by comma,
+// so that both ?ids=1&ids=2 and ?ids=1,2 are decoded to []int{1, 2}.
+func (p *Context) ReadQueryCSV(data interface{}, fields ...string) error {
+	values := p.Query()
+	if len(values) == 0 {
+		return nil
+	}
+
+	return p.fail(decoderQuery.Decode(data, splitValues(values, fields)))
 }
 
 //add header to the response.
@@ -98,72 +562,252 @@ func (p *Context) DelHeader(name string) {
 	p.W.Header().Del(name)
 }
 
-//h may be nil
-func (p *Context) WriteHeader(statusCode int, h http.Header) {
-	if h != nil {
-		hto := p.W.Header()
-		for k, v := range h {
-			hto[k] = v
+//set all headers of h to the response.
+func (p *Context) SetHeaders(h http.Header) {
+	hto := p.W.Header()
+	for k, v := range h {
+		hto[k] = v
+	}
+}
+
+func (p *Context) SetContentType(t string) {
+	p.SetHeader(headerTypeContentType, t)
+}
+
+func (p *Context) SetContentLength(n int64) {
+	p.SetHeader(headerTypeContentLength, strconv.FormatInt(n, 10))
+}
+
+func (p *Context) SetLocation(url string) {
+	p.SetHeader(headerTypeLocation, url)
+}
+
+//d is rounded up to seconds.
+func (p *Context) SetRetryAfter(d time.Duration) {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	p.SetHeader(headerTypeRetryAfter, strconv.FormatInt(seconds, 10))
+}
+
+//disposition should be "attachment" or "inline", filename may be empty.
+func (p *Context) SetContentDisposition(disposition, filename string) {
+	if filename == "" {
+		p.SetHeader(headerTypeContentDisposition, disposition)
+	} else {
+		p.SetHeader(headerTypeContentDisposition, contentDisposition(disposition, filename))
+	}
+}
+
+//append <url>; rel="rel" to the Link header, example: AddLink("/items?page=2", "next").
+func (p *Context) AddLink(url, rel string) {
+	link := "<" + url + ">; rel=" + strconv.Quote(rel)
+	if prior := p.W.Header().Get(headerTypeLink); prior != "" {
+		link = prior + ", " + link
+	}
+	p.SetHeader(headerTypeLink, link)
+}
+
+//declare the trailers, it should be called before the body is written.
+func (p *Context) DeclareTrailer(names ...string) {
+	for _, name := range names {
+		p.AddHeader(headerTypeTrailer, http.CanonicalHeaderKey(name))
+	}
+}
+
+//set the trailer after the body is written, the undeclared name is sent by http.TrailerPrefix.
+func (p *Context) SetTrailer(name, value string) {
+	name = http.CanonicalHeaderKey(name)
+	h := p.W.Header()
+	for _, v := range h.Values(headerTypeTrailer) {
+		for _, declared := range strings.Split(v, ",") {
+			if http.CanonicalHeaderKey(strings.TrimSpace(declared)) == name {
+				h.Set(name, value)
+				return
+			}
 		}
 	}
+	h.Set(http.TrailerPrefix+name, value)
+}
+
+//h may be nil
+func (p *Context) WriteHeader(statusCode int, h http.Header) {
+	p.SetHeaders(h)
 	p.W.WriteHeader(statusCode)
 }
 
+//write the status code only, Status returns the code written.
+func (p *Context) WriteStatus(code int) {
+	p.W.WriteHeader(code)
+}
+
 func (p *Context) Write(data []byte) (n int, err error) {
-	return p.W.Write(data)
+	n, err = p.W.Write(data)
+	p.fail(err)
+
+	return
 }
 
+//str is HTML escaped, use WriteRaw to write it verbatim.
 func (p *Context) WriteString(str string) error {
 	_, err := p.W.Write([]byte(html.EscapeString(str)))
 
-	return err
+	return p.fail(err)
 }
 
+//str is written without escaping.
+func (p *Context) WriteRaw(str string) error {
+	_, err := io.WriteString(p.W, str)
+
+	return p.fail(err)
+}
+
+//the HTML characters are escaped unless it's disabled by SetJSONHTMLEscape.
 func (p *Context) WriteDataJSON(data []byte) error {
 	var buf bytes.Buffer
-	json.HTMLEscape(&buf, data)
+	escapeJSON(&buf, data)
 	p.SetHeader(headerTypeContentType, headerTypeContentJSON)
 	_, err := buf.WriteTo(p.W)
 
-	return err
+	return p.fail(err)
+}
+
+//set whether WriteJSON and the other JSON writers escape <, > and & in JSON, it's enabled by default.
+func SetJSONHTMLEscape(on bool) {
+	jsonHTMLEscape = on
+}
+
+func escapeJSON(buf *bytes.Buffer, data []byte) {
+	if jsonHTMLEscape {
+		json.HTMLEscape(buf, data)
+	} else {
+		buf.Write(data)
+	}
 }
 
 func (p *Context) WriteJSON(v interface{}) error {
+	return p.Render(JSON{Data: v})
+}
+
+func (p *Context) WriteJSONIndent(v interface{}, prefix, indent string) error {
+	data, err := json.MarshalIndent(v, prefix, indent)
+	if err != nil {
+		return p.fail(err)
+	}
+
+	return p.WriteDataJSON(data)
+}
+
+//callback may contain letters, digits, '_', '$' and '.'
+func (p *Context) WriteJSONP(callback string, v interface{}) error {
+	if !isCallbackName(callback) {
+		return p.fail(errCallbackName)
+	}
+
 	data, err := json.Marshal(v)
 	if err == nil {
-		err = p.WriteDataJSON(data)
+		var buf bytes.Buffer
+		buf.WriteString(callback)
+		buf.WriteByte('(')
+		json.HTMLEscape(&buf, data)
+		buf.WriteString(");")
+		p.SetHeader(headerTypeContentType, headerTypeContentJS)
+		_, err = buf.WriteTo(p.W)
 	}
 
-	return err
+	return p.fail(err)
+}
+
+func isCallbackName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$' || c == '.') {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (p *Context) WriteDataXML(data []byte) error {
 	p.SetHeader(headerTypeContentType, headerTypeContentXML)
 	_, err := p.W.Write(data)
 
-	return err
+	return p.fail(err)
 }
 
 func (p *Context) WriteXML(v interface{}) error {
-	data, err := xml.Marshal(v)
-	if err == nil {
-		err = p.WriteDataXML(data)
+	return p.Render(XML{Data: v})
+}
+
+//write the indented XML with the declaration <?xml version="1.0" encoding="UTF-8"?>
+func (p *Context) WriteXMLIndent(v interface{}, prefix, indent string) error {
+	data, err := xml.MarshalIndent(v, prefix, indent)
+	if err != nil {
+		return p.fail(err)
 	}
 
-	return err
+	return p.WriteDataXML(append([]byte(xml.Header), data...))
 }
 
-func (p *Context) WriteHTML(data string) error {
-	p.SetHeader(headerTypeContentType, headerTypeContentHTML)
+func (p *Context) WriteDataYAML(data []byte) error {
+	p.SetHeader(headerTypeContentType, headerTypeContentYAML)
+	_, err := p.W.Write(data)
 
-	return p.WriteString(data)
+	return p.fail(err)
 }
 
-func (p *Context) WriteText(data string) error {
-	p.SetHeader(headerTypeContentType, headerTypeContentText)
-	_, err := p.Write([]byte(data))
+func (p *Context) WriteYAML(v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return p.fail(err)
+	}
 
-	return err
+	return p.WriteDataYAML(data)
+}
+
+func (p *Context) WriteDataProto(data []byte) error {
+	p.SetHeader(headerTypeContentType, headerTypeContentProto)
+	_, err := p.W.Write(data)
+
+	return p.fail(err)
+}
+
+func (p *Context) WriteProto(m proto.Message) error {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return p.fail(err)
+	}
+
+	return p.WriteDataProto(data)
+}
+
+func (p *Context) WriteDataMsgPack(data []byte) error {
+	p.SetHeader(headerTypeContentType, headerTypeContentMsgPack)
+	_, err := p.W.Write(data)
+
+	return p.fail(err)
+}
+
+func (p *Context) WriteMsgPack(v interface{}) error {
+	data, err := msgpack.Marshal(v)
+	if err != nil {
+		return p.fail(err)
+	}
+
+	return p.WriteDataMsgPack(data)
+}
+
+//data is written without escaping.
+func (p *Context) WriteHTML(data string) error {
+	return p.Render(HTML{Data: data})
+}
+
+func (p *Context) WriteText(data string) error {
+	return p.Render(Text{Data: data})
 }
 
 //read header from request
@@ -171,29 +815,216 @@ func (p *Context) GetHeader(name string) string {
 	return p.R.Header.Get(name)
 }
 
+//read all values of the header from request
+func (p *Context) GetHeaders(name string) []string {
+	return p.R.Header.Values(name)
+}
+
+//split all values of the header by comma, the tokens are trimmed and the empty ones are skipped.
+//return nil if the header is absent.
+func (p *Context) GetHeaderList(name string) []string {
+	var list []string
+	for _, v := range p.R.Header.Values(name) {
+		for _, token := range strings.Split(v, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				list = append(list, token)
+			}
+		}
+	}
+
+	return list
+}
+
+//return the token of "Authorization: Bearer <token>"
+func (p *Context) BearerToken() (string, error) {
+	auth := p.GetHeader(headerTypeAuthorization)
+	if auth == "" {
+		return "", errNoAuthorization
+	}
+
+	const prefix = "bearer "
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", errBearerToken
+	}
+	token := strings.TrimSpace(auth[len(prefix):])
+	if token == "" {
+		return "", errBearerToken
+	}
+
+	return token, nil
+}
+
+//return the claims of the JWT in the Bearer token, the signature is NOT verified.
+//it's only for logging or routing, don't use it for authentication or authorization.
+func (p *Context) JWTClaims() (map[string]interface{}, error) {
+	token, err := p.BearerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errJWT
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, errJWT
+	}
+
+	var claims map[string]interface{}
+	if err = json.Unmarshal(data, &claims); err != nil || claims == nil {
+		return nil, errJWT
+	}
+
+	return claims, nil
+}
+
+//ok is false when the Authorization header is missing or malformed.
+func (p *Context) BasicAuth() (username, password string, ok bool) {
+	return p.R.BasicAuth()
+}
+
+//write WWW-Authenticate header and 401 if the request has no basic auth credentials.
+func (p *Context) RequireBasicAuth(realm string) (username, password string, ok bool) {
+	if username, password, ok = p.BasicAuth(); !ok {
+		p.SetHeader(headerTypeWWWAuthenticate, fmt.Sprintf("Basic realm=%q", realm))
+		p.Error(http.StatusUnauthorized)
+	}
+
+	return
+}
+
 func (p *Context) GetBody() ([]byte, error) {
-	if p.R.Body == nil {
-		return nil, errEmptyBody
+	if p.body != nil {
+		p.R.Body = ioutil.NopCloser(bytes.NewReader(p.body))
+		return p.body, nil
+	} else if p.R.Body == nil {
+		return nil, p.fail(errEmptyBody)
 	} else {
-		return ioutil.ReadAll(p.R.Body)
+		data, err := ioutil.ReadAll(p.BodyReader())
+		return data, p.fail(err)
+	}
+}
+
+//return the body to be read as a stream, it's limited by MaxBody.
+//the body of Content-Encoding gzip or deflate is decompressed, MaxBody limits the decompressed size too.
+func (p *Context) BodyReader() io.Reader {
+	if p.body != nil {
+		return bytes.NewReader(p.body)
+	} else if p.R.Body == nil {
+		return http.NoBody
+	}
+
+	var body io.Reader = p.R.Body
+	if p.MaxBody > 0 {
+		body = http.MaxBytesReader(p.W, p.R.Body, p.MaxBody)
+	}
+	if encoding := p.GetHeader(headerTypeContentEncoding); encoding != "" {
+		body = newDecodeReader(body, encoding, p.MaxBody)
+	}
+
+	return body
+}
+
+//read the body once and keep it, so that the body can be read more than once.
+//the body size should not be larger than MaxMem.
+//the decoded body replaces the request body, Content-Encoding is removed and Content-Length is updated,
+//so that the request is consistent when it's read again, example: Proxy.
+func (p *Context) BufferBody() error {
+	if p.body != nil {
+		return nil
+	} else if p.R.Body == nil {
+		return p.fail(errEmptyBody)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(p.BodyReader(), p.MaxMem+1))
+	p.R.Body.Close()
+	if err == nil {
+		if int64(len(data)) > p.MaxMem {
+			err = errBodyTooLarge
+		} else {
+			p.body = data
+			p.R.Body = ioutil.NopCloser(bytes.NewReader(data))
+			p.R.ContentLength = int64(len(data))
+			p.R.Header.Del(headerTypeContentEncoding)
+			p.R.Header.Set(headerTypeContentLength, strconv.Itoa(len(data)))
+		}
 	}
+
+	return p.fail(err)
+}
+
+//return the buffered body, the same buffer is returned to all callers.
+//the buffer holds a copy, so writing to it doesn't change the body returned by GetBody and BodyReader.
+//use Bytes of the buffer to share it, reading from the buffer consumes it for the other callers.
+func (p *Context) BodyBuffer() (*bytes.Buffer, error) {
+	if p.bodyBuffer == nil {
+		if err := p.BufferBody(); err != nil {
+			return nil, err
+		}
+		p.bodyBuffer = bytes.NewBuffer(append([]byte(nil), p.body...))
+	}
+
+	return p.bodyBuffer, nil
 }
 
 func (p *Context) UnmarshalBody(v interface{}, unmarshaler UnmarshalerFunc) error {
 	data, err := p.GetBody()
 	if err == nil {
-		err = unmarshaler(data, v)
+		err = p.fail(unmarshaler(data, v))
 	}
 
 	return err
 }
 
 func (p *Context) ReadJSON(v interface{}) error {
-	return p.UnmarshalBody(v, UnmarshalerFunc(json.Unmarshal))
+	err := p.UnmarshalBody(v, UnmarshalerFunc(json.Unmarshal))
+	if err == nil {
+		err = p.fail(validate(v))
+	}
+
+	return err
+}
+
+//read the JSON object of the body.
+func (p *Context) ReadJSONMap() (map[string]interface{}, error) {
+	var m map[string]interface{}
+	err := p.UnmarshalBody(&m, UnmarshalerFunc(json.Unmarshal))
+
+	return m, err
+}
+
+//read the JSON array of the body.
+func (p *Context) ReadJSONArray() ([]interface{}, error) {
+	var a []interface{}
+	err := p.UnmarshalBody(&a, UnmarshalerFunc(json.Unmarshal))
+
+	return a, err
 }
 
 func (p *Context) ReadXML(v interface{}) error {
-	return p.UnmarshalBody(v, UnmarshalerFunc(xml.Unmarshal))
+	err := p.UnmarshalBody(v, UnmarshalerFunc(xml.Unmarshal))
+	if err == nil {
+		err = p.fail(validate(v))
+	}
+
+	return err
+}
+
+func (p *Context) ReadYAML(v interface{}) error {
+	return p.UnmarshalBody(v, UnmarshalerFunc(yaml.Unmarshal))
+}
+
+func (p *Context) ReadProto(m proto.Message) error {
+	return p.UnmarshalBody(m, UnmarshalerFunc(unmarshalProto))
+}
+
+func unmarshalProto(data []byte, v interface{}) error {
+	return proto.Unmarshal(data, v.(proto.Message))
+}
+
+func (p *Context) ReadMsgPack(v interface{}) error {
+	return p.UnmarshalBody(v, UnmarshalerFunc(msgpack.Unmarshal))
 }
 
 func (p *Context) ReadHTML() (string, error) {
@@ -216,54 +1047,256 @@ func (p *Context) ReadText() (string, error) {
 	return str, err
 }
 
+//parse the multipart form once, FormValue, FormFile and the other methods read the parsed form then.
+//the total size is limited by MaxUpload so that a huge body can't fill the temp disk, errUploadTooLarge is returned if it's exceeded.
+//http.ErrNotMultipart is returned if the request isn't multipart/form-data.
+func (p *Context) ParseMultipart() error {
+	if p.formParsed {
+		return p.formErr
+	}
+	p.formParsed = true
+
+	if p.MaxUpload > 0 && p.R.MultipartForm == nil && p.R.Body != nil {
+		if p.R.ContentLength > p.MaxUpload {
+			p.formErr = errUploadTooLarge
+			return p.formErr
+		}
+		p.R.Body = &uploadReader{ReadCloser: http.MaxBytesReader(p.W, p.R.Body, p.MaxUpload), max: p.MaxUpload}
+	}
+
+	err := p.R.ParseMultipartForm(p.MaxMem)
+	if errors.Is(err, errUploadTooLarge) {
+		err = errUploadTooLarge
+	}
+	p.formErr = err
+
+	return err
+}
+
 func (p *Context) FormFile(key string) (multipart.File, *multipart.FileHeader, error) {
-	if err := p.R.ParseMultipartForm(p.MaxMem); err != nil {
-		return nil, nil, err
+	if err := p.ParseMultipart(); err != nil {
+		return nil, nil, p.fail(err)
+	}
+
+	file, info, err := p.R.FormFile(key)
+
+	return file, info, p.fail(err)
+}
+
+//uploadReader replaces the error of MaxBytesReader by errUploadTooLarge.
+type uploadReader struct {
+	io.ReadCloser
+	n   int64
+	max int64
+}
+
+func (p *uploadReader) Read(data []byte) (int, error) {
+	n, err := p.ReadCloser.Read(data)
+	p.n += int64(n)
+	if err != nil && err != io.EOF && p.n >= p.max {
+		err = errUploadTooLarge
 	}
 
-	return p.R.FormFile(key)
+	return n, err
+}
+
+//read the multipart body part by part, so that the large files can be streamed without temp files.
+//http.ErrNotMultipart is returned if the request isn't multipart/form-data.
+//it can't be mixed with FormFile and the other methods parsing the multipart form.
+func (p *Context) MultipartReader() (*multipart.Reader, error) {
+	return p.R.MultipartReader()
 }
 
 func (p *Context) UploadFile(key string, createFile func(string) io.WriteCloser) error {
 	file, info, err := p.FormFile(key)
-	if err == nil {
-		defer file.Close()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
 
-		if out := createFile(info.Filename); out != nil {
-			defer out.Close()
-			_, err = io.Copy(out, file)
-		} else {
-			err = errCreateFile
+	return p.fail(copyFile(file, info.Filename, createFile))
+}
+
+//return all files of key, for <input type="file" multiple>.
+func (p *Context) FormFiles(key string) ([]*multipart.FileHeader, error) {
+	if err := p.ParseMultipart(); err != nil {
+		return nil, p.fail(err)
+	}
+
+	if files := p.R.MultipartForm.File[key]; len(files) > 0 {
+		return files, nil
+	}
+
+	return nil, p.fail(http.ErrMissingFile)
+}
+
+//return the sorted field names of the uploaded files, http.ErrNotMultipart is returned if the request isn't multipart/form-data.
+func (p *Context) UploadedFileFields() ([]string, error) {
+	if err := p.ParseMultipart(); err != nil {
+		return nil, p.fail(err)
+	}
+
+	fields := make([]string, 0, len(p.R.MultipartForm.File))
+	for key := range p.R.MultipartForm.File {
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+func (p *Context) UploadFiles(key string, createFile func(string) io.WriteCloser) error {
+	files, err := p.FormFiles(key)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range files {
+		file, err := info.Open()
+		if err != nil {
+			return p.fail(err)
+		}
+		err = copyFile(file, info.Filename, createFile)
+		file.Close()
+		if err != nil {
+			return p.fail(err)
 		}
 	}
 
+	return nil
+}
+
+func copyFile(file multipart.File, name string, createFile func(string) io.WriteCloser) error {
+	out := createFile(name)
+	if out == nil {
+		return errCreateFile
+	}
+	defer out.Close()
+	_, err := io.Copy(out, file)
+
 	return err
 }
 
+//return the content and the name of the uploaded file, the size should not be larger than MaxMem.
+func (p *Context) ReadFormFileBytes(key string) ([]byte, string, error) {
+	file, info, err := p.FormFile(key)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(file, p.MaxMem+1))
+	if err == nil && int64(len(data)) > p.MaxMem {
+		err = errFileTooLarge
+	}
+	if err != nil {
+		return nil, "", p.fail(err)
+	}
+
+	return data, info.Filename, nil
+}
+
+//save the uploaded file of key to dstPath.
+func (p *Context) SaveUploadedFile(key, dstPath string) error {
+	file, _, err := p.FormFile(key)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	out, err := os.Create(dstPath)
+	if err != nil {
+		return p.fail(err)
+	}
+	if _, err = io.Copy(out, file); err != nil {
+		out.Close()
+		return p.fail(err)
+	}
+
+	return p.fail(out.Close())
+}
+
+//configure the decoder of ReadForm, example: SetFormDecoderOptions(func(d *schema.Decoder) { d.IgnoreUnknownKeys(false) }).
+//it should be called before serving, the decoder is shared by all requests.
+func SetFormDecoderOptions(opts ...DecoderOption) {
+	for _, opt := range opts {
+		opt(decoderForm)
+	}
+}
+
+//configure the decoder of ReadQuery and ReadQueryCSV, see SetFormDecoderOptions.
+func SetQueryDecoderOptions(opts ...DecoderOption) {
+	for _, opt := range opts {
+		opt(decoderQuery)
+	}
+}
+
+//register the converter of t for ReadForm, example: parse time.Time by RFC 3339.
+//it should be called before serving, the decoder is shared by all requests.
+func RegisterFormConverter(t reflect.Type, fn schema.Converter) {
+	decoderForm.RegisterConverter(reflect.Zero(t).Interface(), fn)
+}
+
+//register the converter of t for ReadQuery and ReadQueryCSV, see RegisterFormConverter.
+func RegisterQueryConverter(t reflect.Type, fn schema.Converter) {
+	decoderQuery.RegisterConverter(reflect.Zero(t).Interface(), fn)
+}
+
 func init() {
 	decoderForm = schema.NewDecoder()
 	decoderQuery = schema.NewDecoder()
 	decoderForm.SetAliasTag("form")
+	decoderForm.IgnoreUnknownKeys(true)
 	decoderQuery.SetAliasTag("url")
 }
 
 type (
 	UnmarshalerFunc func(data []byte, v interface{}) error
+	DecoderOption   func(d *schema.Decoder)
 )
 
 var (
 	errUnknownDataType = errors.New("Unknown data type")
 	errDataType        = errors.New("Error data type")
 	errEmptyBody       = errors.New("Empty body")
+	errBodyTooLarge    = errors.New("Body too large")
+	errRedirectCode    = errors.New("Error redirect code")
+	errCallbackName    = errors.New("Error callback name")
+	errNoAuthorization = errors.New("No authorization")
+	errBearerToken     = errors.New("Error bearer token")
+	errJWT             = errors.New("Error JWT")
+	errNotFlusher      = errors.New("Flush not supported")
+	errNotHijacker     = errors.New("Hijack not supported")
 	errCreateFile      = errors.New("Create file failed")
+	errIsDir           = errors.New("Is a directory")
+	errFileTooLarge    = errors.New("File too large")
+	errUploadTooLarge  = errors.New("Upload too large")
 	decoderForm        *schema.Decoder
 	decoderQuery       *schema.Decoder
+	defaultMaxMem      int64 = 0x4000000
+	jsonHTMLEscape           = true
 )
 
 const (
-	headerTypeContentType = "Content-Type"
-	headerTypeContentJSON = "application/json;charset=utf-8"
-	headerTypeContentXML  = "text/xml;charset=utf-8"
-	headerTypeContentHTML = "text/html;charset=utf-8"
-	headerTypeContentText = "text/plain;charset=utf-8"
+	headerTypeWWWAuthenticate    = "WWW-Authenticate"
+	headerTypeAuthorization      = "Authorization"
+	headerTypeRequestedWith      = "X-Requested-With"
+	headerTypeUpgrade            = "Upgrade"
+	headerTypeRequestID          = "X-Request-ID"
+	headerTypeContentDisposition = "Content-Disposition"
+	headerTypeLocation           = "Location"
+	headerTypeLink               = "Link"
+	headerTypeTrailer            = "Trailer"
+	headerTypeRetryAfter         = "Retry-After"
+	headerTypeContentType        = "Content-Type"
+	headerTypeContentJSON        = "application/json;charset=utf-8"
+	headerTypeContentXML         = "text/xml;charset=utf-8"
+	headerTypeContentHTML        = "text/html;charset=utf-8"
+	headerTypeContentText        = "text/plain;charset=utf-8"
+	headerTypeContentJS          = "application/javascript;charset=utf-8"
+	headerTypeContentYAML        = "text/yaml;charset=utf-8"
+	headerTypeContentProto       = "application/x-protobuf"
+	headerTypeContentMsgPack     = "application/msgpack"
+	mimeMultipartForm            = "multipart/form-data"
+	mimeURLEncodedForm           = "application/x-www-form-urlencoded"
 )
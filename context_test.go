@@ -0,0 +1,40 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteHTMLRaw(t *testing.T) {
+	w := httptest.NewRecorder()
+	p := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := p.WriteHTML("<b>x</b>"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := w.Body.String(); got != "<b>x</b>" {
+		t.Errorf("body = %q, want <b>x</b>", got)
+	}
+	if got := w.Header().Get(headerTypeContentType); got != headerTypeContentHTML {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func TestBodyBufferCopy(t *testing.T) {
+	p := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello")))
+	buf, err := p.BodyBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same, _ := p.BodyBuffer(); same != buf {
+		t.Error("BodyBuffer should return the same buffer")
+	}
+
+	buf.Reset()
+	buf.WriteString("HELLO")
+	if data, err := p.GetBody(); err != nil || string(data) != "hello" {
+		t.Errorf("GetBody = %q, %v, want the original body", data, err)
+	}
+}
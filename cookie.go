@@ -0,0 +1,147 @@
+package https
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func (p *Context) SetCookie(cookie *http.Cookie) {
+	http.SetCookie(p.W, cookie)
+}
+
+//set cookie with Path="/" and HttpOnly, value is query escaped.
+//maxAge=0 means a session cookie.
+func (p *Context) SetCookieValue(name, value string, maxAge int) {
+	p.SetCookie(&http.Cookie{
+		Name:     name,
+		Value:    url.QueryEscape(value),
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+	})
+}
+
+//read cookie from request, the value is query unescaped.
+func (p *Context) GetCookie(name string) (string, error) {
+	cookie, err := p.R.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	return url.QueryUnescape(cookie.Value)
+}
+
+//write an expired cookie to remove it from the client.
+func (p *Context) DeleteCookie(name string) {
+	p.SetCookie(&http.Cookie{
+		Name:     name,
+		Path:     "/",
+		MaxAge:   -1,
+		Expires:  time.Unix(0, 0),
+		HttpOnly: true,
+	})
+}
+
+//set cookie with the value signed by HMAC-SHA256, the value is readable by the client.
+func (p *Context) SetSignedCookie(name, value string, key []byte) {
+	p.setCookieData(name, base64.RawURLEncoding.EncodeToString([]byte(value))+"."+signCookie(name, value, key))
+}
+
+//return error if the cookie is tampered.
+func (p *Context) GetSignedCookie(name string, key []byte) (string, error) {
+	cookie, err := p.R.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	pos := strings.IndexByte(cookie.Value, '.')
+	if pos < 0 {
+		return "", errCookieSign
+	}
+	value, err := base64.RawURLEncoding.DecodeString(cookie.Value[:pos])
+	if err != nil || !hmac.Equal([]byte(cookie.Value[pos+1:]), []byte(signCookie(name, string(value), key))) {
+		return "", errCookieSign
+	}
+
+	return string(value), nil
+}
+
+//set cookie with the value encrypted by AES-GCM, key should be 16, 24 or 32 bytes.
+func (p *Context) SetSecureCookie(name, value string, key []byte) error {
+	aead, err := newCookieAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	p.setCookieData(name, base64.RawURLEncoding.EncodeToString(data))
+
+	return nil
+}
+
+func (p *Context) GetSecureCookie(name string, key []byte) (string, error) {
+	aead, err := newCookieAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	cookie, err := p.R.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil || len(data) < aead.NonceSize() {
+		return "", errCookieSign
+	}
+	size := aead.NonceSize()
+	value, err := aead.Open(nil, data[:size], data[size:], []byte(name))
+	if err != nil {
+		return "", errCookieSign
+	}
+
+	return string(value), nil
+}
+
+func (p *Context) setCookieData(name, data string) {
+	p.SetCookie(&http.Cookie{
+		Name:     name,
+		Value:    data,
+		Path:     "/",
+		HttpOnly: true,
+	})
+}
+
+//the name is signed too, so that the value can't be moved to another cookie.
+func signCookie(name, value string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newCookieAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+var errCookieSign = errors.New("Error cookie signature")
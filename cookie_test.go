@@ -0,0 +1,108 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+//return the value of the cookie name written to w.
+func responseCookie(t *testing.T, w *httptest.ResponseRecorder, name string) string {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c.Value
+		}
+	}
+	t.Fatalf("cookie %s is not set", name)
+
+	return ""
+}
+
+func newCookieContext(name, value string) *Context {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: name, Value: value})
+
+	return NewContext(httptest.NewRecorder(), r)
+}
+
+//replace the first character, so that the value is changed but still valid base64.
+//the last character isn't changed, since it may have the padding bits only.
+func tamper(value string) string {
+	first := "A"
+	if strings.HasPrefix(value, "A") {
+		first = "B"
+	}
+
+	return first + value[1:]
+}
+
+func TestSignedCookie(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	w := httptest.NewRecorder()
+	NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil)).SetSignedCookie("user", "alice", key)
+	value := responseCookie(t, w, "user")
+	pos := strings.IndexByte(value, '.')
+
+	tests := []struct {
+		desc  string
+		name  string
+		value string
+		key   []byte
+		ok    bool
+	}{
+		{"valid", "user", value, key, true},
+		{"tampered value", "user", tamper(value[:pos]) + value[pos:], key, false},
+		{"tampered signature", "user", value[:pos+1] + tamper(value[pos+1:]), key, false},
+		{"renamed cookie", "admin", value, key, false},
+		{"wrong key", "user", value, []byte("fedcba9876543210"), false},
+		{"no signature", "user", value[:pos], key, false},
+	}
+	for _, tt := range tests {
+		got, err := newCookieContext(tt.name, tt.value).GetSignedCookie(tt.name, tt.key)
+		if tt.ok && (err != nil || got != "alice") {
+			t.Errorf("%s: GetSignedCookie = %q, %v", tt.desc, got, err)
+		} else if !tt.ok && err != errCookieSign {
+			t.Errorf("%s: GetSignedCookie = %q, %v, want errCookieSign", tt.desc, got, err)
+		}
+	}
+}
+
+func TestSecureCookie(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	w := httptest.NewRecorder()
+	if err := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil)).SetSecureCookie("sid", "secret", key); err != nil {
+		t.Fatal(err)
+	}
+	value := responseCookie(t, w, "sid")
+	if strings.Contains(value, "secret") {
+		t.Fatalf("cookie value %q isn't encrypted", value)
+	}
+
+	tests := []struct {
+		desc  string
+		name  string
+		value string
+		key   []byte
+		ok    bool
+	}{
+		{"valid", "sid", value, key, true},
+		{"tampered", "sid", tamper(value), key, false},
+		{"renamed cookie", "other", value, key, false},
+		{"wrong key", "sid", value, []byte("fedcba9876543210"), false},
+		{"too short", "sid", value[:8], key, false},
+		{"not base64", "sid", "!!!", key, false},
+	}
+	for _, tt := range tests {
+		got, err := newCookieContext(tt.name, tt.value).GetSecureCookie(tt.name, tt.key)
+		if tt.ok && (err != nil || got != "secret") {
+			t.Errorf("%s: GetSecureCookie = %q, %v", tt.desc, got, err)
+		} else if !tt.ok && err != errCookieSign {
+			t.Errorf("%s: GetSecureCookie = %q, %v, want errCookieSign", tt.desc, got, err)
+		}
+	}
+
+	if err := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil)).SetSecureCookie("sid", "secret", []byte("short")); err == nil {
+		t.Error("SetSecureCookie with invalid key size should fail")
+	}
+}
@@ -0,0 +1,93 @@
+package https
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CORSOptions struct {
+	AllowCredentials bool
+	MaxAge           time.Duration //cache time of the preflight result, 0 means not set
+	Methods          []string      //allowed methods, the requested method is allowed if empty
+	Headers          []string      //allowed headers, the requested headers are allowed if empty
+	ExposeHeaders    []string
+}
+
+//set Access-Control-Allow-* headers if the Origin of the request is in origins, "*" allows any origin.
+//the origin allowed by "*" gets Access-Control-Allow-Origin: * without credentials, so list the origins to allow credentials.
+//the preflight request is answered with 204 and true is returned, so the handler should return.
+//opts may be nil.
+func (p *Context) CORS(origins []string, opts *CORSOptions) bool {
+	origin := p.GetHeader(headerTypeOrigin)
+	if origin == "" {
+		return false
+	}
+	if opts == nil {
+		opts = &CORSOptions{}
+	}
+
+	h := p.W.Header()
+	h.Add(headerTypeVary, headerTypeOrigin)
+	preflight := p.R.Method == http.MethodOptions && p.GetHeader(headerTypeCORSRequestMethod) != ""
+	if allowed, wildcard := isAllowedOrigin(origins, origin); allowed {
+		if wildcard {
+			h.Set(headerTypeCORSAllowOrigin, "*")
+		} else {
+			h.Set(headerTypeCORSAllowOrigin, origin)
+		}
+		if opts.AllowCredentials && !wildcard {
+			h.Set(headerTypeCORSAllowCredentials, "true")
+		}
+
+		if preflight {
+			if len(opts.Methods) > 0 {
+				h.Set(headerTypeCORSAllowMethods, strings.Join(opts.Methods, ", "))
+			} else {
+				h.Set(headerTypeCORSAllowMethods, p.GetHeader(headerTypeCORSRequestMethod))
+			}
+			if len(opts.Headers) > 0 {
+				h.Set(headerTypeCORSAllowHeaders, strings.Join(opts.Headers, ", "))
+			} else if headers := p.GetHeader(headerTypeCORSRequestHeaders); headers != "" {
+				h.Set(headerTypeCORSAllowHeaders, headers)
+			}
+			if opts.MaxAge > 0 {
+				h.Set(headerTypeCORSMaxAge, strconv.FormatInt(int64(opts.MaxAge/time.Second), 10))
+			}
+		} else if len(opts.ExposeHeaders) > 0 {
+			h.Set(headerTypeCORSExposeHeaders, strings.Join(opts.ExposeHeaders, ", "))
+		}
+	}
+
+	if preflight {
+		p.W.WriteHeader(http.StatusNoContent)
+	}
+
+	return preflight
+}
+
+//wildcard is true if origin is allowed by "*" only.
+func isAllowedOrigin(origins []string, origin string) (allowed, wildcard bool) {
+	for _, v := range origins {
+		if strings.EqualFold(v, origin) {
+			return true, false
+		} else if v == "*" {
+			wildcard = true
+		}
+	}
+
+	return wildcard, wildcard
+}
+
+const (
+	headerTypeOrigin               = "Origin"
+	headerTypeCORSRequestMethod    = "Access-Control-Request-Method"
+	headerTypeCORSRequestHeaders   = "Access-Control-Request-Headers"
+	headerTypeCORSAllowOrigin      = "Access-Control-Allow-Origin"
+	headerTypeCORSAllowCredentials = "Access-Control-Allow-Credentials"
+	headerTypeCORSAllowMethods     = "Access-Control-Allow-Methods"
+	headerTypeCORSAllowHeaders     = "Access-Control-Allow-Headers"
+	headerTypeCORSExposeHeaders    = "Access-Control-Expose-Headers"
+	headerTypeCORSMaxAge           = "Access-Control-Max-Age"
+)
@@ -0,0 +1,33 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSWildcardCredentials(t *testing.T) {
+	tests := []struct {
+		origins     []string
+		allowOrigin string
+		credentials string
+	}{
+		{[]string{"*"}, "*", ""},
+		{[]string{"https://a.com"}, "https://a.com", "true"},
+		{[]string{"*", "https://a.com"}, "https://a.com", "true"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(headerTypeOrigin, "https://a.com")
+		w := httptest.NewRecorder()
+		NewContext(w, r).CORS(tt.origins, &CORSOptions{AllowCredentials: true})
+
+		h := w.Header()
+		if got := h.Get(headerTypeCORSAllowOrigin); got != tt.allowOrigin {
+			t.Errorf("%v: Allow-Origin = %q, want %q", tt.origins, got, tt.allowOrigin)
+		}
+		if got := h.Get(headerTypeCORSAllowCredentials); got != tt.credentials {
+			t.Errorf("%v: Allow-Credentials = %q, want %q", tt.origins, got, tt.credentials)
+		}
+	}
+}
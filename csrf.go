@@ -0,0 +1,63 @@
+package https
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+)
+
+//return the CSRF token of the client, a new token is generated and stored in cookie if not exists.
+//the token should be sent back by header X-CSRF-Token or form field _csrf.
+func (p *Context) CSRFToken() string {
+	if p.csrfToken != "" {
+		return p.csrfToken
+	}
+	if token, err := p.GetCookie(csrfCookieName); err == nil && token != "" {
+		p.csrfToken = token
+		return token
+	}
+
+	data := make([]byte, csrfTokenSize)
+	if _, err := rand.Read(data); err != nil {
+		panic(err)
+	}
+	p.csrfToken = base64.RawURLEncoding.EncodeToString(data)
+	p.SetCookie(&http.Cookie{
+		Name:     csrfCookieName,
+		Value:    p.csrfToken,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return p.csrfToken
+}
+
+//compare the token from header or form with the cookie.
+func (p *Context) VerifyCSRF() error {
+	expected, err := p.GetCookie(csrfCookieName)
+	if err != nil || expected == "" {
+		return errCSRFToken
+	}
+
+	token := p.GetHeader(headerTypeCSRFToken)
+	if token == "" {
+		token = p.FormValue(csrfFormName)
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return errCSRFToken
+	}
+
+	return nil
+}
+
+var errCSRFToken = errors.New("Error CSRF token")
+
+const (
+	csrfCookieName      = "_csrf"
+	csrfFormName        = "_csrf"
+	csrfTokenSize       = 32
+	headerTypeCSRFToken = "X-CSRF-Token"
+)
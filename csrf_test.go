@@ -0,0 +1,62 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRF(t *testing.T) {
+	w := httptest.NewRecorder()
+	token := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil)).CSRFToken()
+	if token == "" || responseCookie(t, w, csrfCookieName) != token {
+		t.Fatalf("CSRFToken = %q is not stored in cookie", token)
+	}
+
+	tests := []struct {
+		desc   string
+		cookie string
+		header string
+		form   string
+		ok     bool
+	}{
+		{"header", token, token, "", true},
+		{"form", token, "", token, true},
+		{"wrong header", token, tamper(token), "", false},
+		{"wrong form", token, "", tamper(token), false},
+		{"missing token", token, "", "", false},
+		{"missing cookie", "", token, "", false},
+		{"header wins over form", token, tamper(token), token, false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{csrfFormName: {tt.form}}.Encode()))
+		r.Header.Set(headerTypeContentType, mimeURLEncodedForm)
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: csrfCookieName, Value: tt.cookie})
+		}
+		if tt.header != "" {
+			r.Header.Set(headerTypeCSRFToken, tt.header)
+		}
+
+		err := NewContext(httptest.NewRecorder(), r).VerifyCSRF()
+		if tt.ok && err != nil {
+			t.Errorf("%s: VerifyCSRF = %v", tt.desc, err)
+		} else if !tt.ok && err != errCSRFToken {
+			t.Errorf("%s: VerifyCSRF = %v, want errCSRFToken", tt.desc, err)
+		}
+	}
+}
+
+func TestCSRFTokenReused(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: csrfCookieName, Value: "existing"})
+	w := httptest.NewRecorder()
+	if token := NewContext(w, r).CSRFToken(); token != "existing" {
+		t.Errorf("CSRFToken = %q, want the cookie token", token)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("CSRFToken should not set a new cookie when the cookie exists")
+	}
+}
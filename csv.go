@@ -0,0 +1,23 @@
+package https
+
+import (
+	"encoding/csv"
+)
+
+//write records as a CSV attachment named filename.
+func (p *Context) WriteCSV(records [][]string, filename string) error {
+	return p.fail(p.CSVWriter(filename).WriteAll(records))
+}
+
+//set the CSV headers and return a writer writing to the response directly.
+//Flush of the writer must be called after writing, filename may be empty.
+func (p *Context) CSVWriter(filename string) *csv.Writer {
+	p.SetHeader(headerTypeContentType, headerTypeContentCSV)
+	if filename != "" {
+		p.SetContentDisposition("attachment", filename)
+	}
+
+	return csv.NewWriter(p.W)
+}
+
+const headerTypeContentCSV = "text/csv;charset=utf-8"
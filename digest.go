@@ -0,0 +1,106 @@
+package https
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+//verify the body by the Digest header (SHA-256, SHA-512 or MD5) or the Content-MD5 header.
+//the digest is computed over the body as sent, before Content-Encoding is decoded.
+//it should be called before BufferBody, which decodes the body and removes Content-Encoding.
+//the body is buffered by BufferBody, so that it can be read after the verification.
+func (p *Context) VerifyBodyDigest() error {
+	var digests [][2]string
+	for _, v := range strings.Split(p.GetHeader(headerTypeDigest), ",") {
+		if pos := strings.IndexByte(v, '='); pos > 0 {
+			digests = append(digests, [2]string{strings.TrimSpace(v[:pos]), strings.TrimSpace(v[pos+1:])})
+		}
+	}
+	if md5sum := strings.TrimSpace(p.GetHeader(headerTypeContentMD5)); md5sum != "" {
+		digests = append(digests, [2]string{"MD5", md5sum})
+	}
+
+	var raw []byte
+	found := false
+	for _, d := range digests {
+		newHash := digestHashes[strings.ToUpper(d[0])]
+		if newHash == nil {
+			continue
+		}
+		if !found {
+			var err error
+			if raw, err = p.rawBody(); err != nil {
+				return err
+			}
+			found = true
+		}
+
+		sum, err := base64.StdEncoding.DecodeString(d[1])
+		h := newHash()
+		h.Write(raw)
+		if err != nil || !bytes.Equal(sum, h.Sum(nil)) {
+			return p.fail(errBodyDigest)
+		}
+	}
+	if !found {
+		return p.fail(errNoDigest)
+	}
+
+	return nil
+}
+
+//return the body before Content-Encoding is decoded, the decoded body is buffered then.
+func (p *Context) rawBody() ([]byte, error) {
+	if encoding := strings.TrimSpace(p.GetHeader(headerTypeContentEncoding)); encoding == "" || strings.EqualFold(encoding, "identity") {
+		if err := p.BufferBody(); err != nil {
+			return nil, err
+		}
+		return p.body, nil
+	} else if p.R.Body == nil {
+		return nil, p.fail(errEmptyBody)
+	}
+
+	var body io.Reader = p.R.Body
+	if p.MaxBody > 0 {
+		body = http.MaxBytesReader(p.W, p.R.Body, p.MaxBody)
+	}
+	raw, err := ioutil.ReadAll(io.LimitReader(body, p.MaxMem+1))
+	p.R.Body.Close()
+	if err == nil && int64(len(raw)) > p.MaxMem {
+		err = errBodyTooLarge
+	}
+	if err != nil {
+		return nil, p.fail(err)
+	}
+
+	p.R.Body = ioutil.NopCloser(bytes.NewReader(raw))
+	if err = p.BufferBody(); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+var (
+	digestHashes = map[string]func() hash.Hash{
+		"SHA-256": sha256.New,
+		"SHA-512": sha512.New,
+		"MD5":     md5.New,
+	}
+	errBodyDigest = errors.New("Error body digest")
+	errNoDigest   = errors.New("No supported digest")
+)
+
+const (
+	headerTypeDigest     = "Digest"
+	headerTypeContentMD5 = "Content-MD5"
+)
@@ -0,0 +1,51 @@
+package https
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifyBodyDigest(t *testing.T) {
+	body := []byte("hello")
+	md5sum := md5.Sum(body)
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	r.Header.Set(headerTypeContentMD5, base64.StdEncoding.EncodeToString(md5sum[:]))
+	p := NewContext(httptest.NewRecorder(), r)
+	if err := p.VerifyBodyDigest(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := p.GetBody(); err != nil || string(data) != "hello" {
+		t.Errorf("GetBody = %q, %v", data, err)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	r.Header.Set(headerTypeDigest, "SHA-256="+base64.StdEncoding.EncodeToString(md5sum[:]))
+	if err := NewContext(httptest.NewRecorder(), r).VerifyBodyDigest(); err != errBodyDigest {
+		t.Errorf("VerifyBodyDigest of wrong digest = %v", err)
+	}
+}
+
+func TestVerifyBodyDigestGzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("hello"))
+	gz.Close()
+	sum := sha256.Sum256(buf.Bytes())
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(buf.Bytes()))
+	r.Header.Set(headerTypeContentEncoding, "gzip")
+	r.Header.Set(headerTypeDigest, "SHA-256="+base64.StdEncoding.EncodeToString(sum[:]))
+	p := NewContext(httptest.NewRecorder(), r)
+	if err := p.VerifyBodyDigest(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := p.GetBody(); err != nil || string(data) != "hello" {
+		t.Errorf("GetBody = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,111 @@
+package https
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+//StatusCoder is implemented by the errors carrying HTTP status code, see WriteErr.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+//write {"error":{"code":code,"message":message,"details":details}} with the status code.
+//details is optional, example: the validation errors.
+func (p *Context) WriteErrorJSON(code int, message string, details ...interface{}) error {
+	body := errorJSON{Error: errorJSONBody{Code: code, Message: message}}
+	if len(details) > 0 {
+		body.Error.Details = details[0]
+	}
+
+	return p.writeJSONCode(code, &body)
+}
+
+//write err as JSON error with the status code of StatusCoder or RegisterErrorStatus, 500 by default.
+//the message of 5xx is the status text, so that the internal error is not exposed.
+func (p *Context) WriteErr(err error) error {
+	code := errorStatus(err)
+	message := http.StatusText(code)
+	if code < http.StatusInternalServerError {
+		message = err.Error()
+	}
+
+	return p.WriteErrorJSON(code, message)
+}
+
+//write the RFC 7807 problem {"type","title","status","detail","instance"} as application/problem+json.
+//extensions is optional, its fields are added to the problem, example: {"balance": 30}.
+func (p *Context) WriteProblem(status int, title, detail string, extensions ...map[string]interface{}) error {
+	problem := make(map[string]interface{})
+	if len(extensions) > 0 {
+		for k, v := range extensions[0] {
+			problem[k] = v
+		}
+	}
+	if _, ok := problem["type"]; !ok {
+		problem["type"] = "about:blank"
+	}
+	if title == "" {
+		title = http.StatusText(status)
+	}
+	problem["title"] = title
+	problem["status"] = status
+	if detail != "" {
+		problem["detail"] = detail
+	}
+	problem["instance"] = p.R.URL.RequestURI()
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		return p.fail(err)
+	}
+
+	var buf bytes.Buffer
+	escapeJSON(&buf, data)
+	p.SetHeader(headerTypeContentType, mimeProblemJSON)
+	p.W.WriteHeader(status)
+	_, err = buf.WriteTo(p.W)
+
+	return p.fail(err)
+}
+
+//map err to the status code for WriteErr, example: RegisterErrorStatus(sql.ErrNoRows, http.StatusNotFound).
+//the wrapped errors are matched by errors.Is.
+func RegisterErrorStatus(err error, code int) {
+	errorStatuses = append(errorStatuses, errorStatusItem{err: err, code: code})
+}
+
+func errorStatus(err error) int {
+	var coder StatusCoder
+	if errors.As(err, &coder) {
+		return coder.StatusCode()
+	}
+	for _, item := range errorStatuses {
+		if errors.Is(err, item.err) {
+			return item.code
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+type errorJSON struct {
+	Error errorJSONBody `json:"error"`
+}
+
+type errorJSONBody struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+type errorStatusItem struct {
+	err  error
+	code int
+}
+
+var errorStatuses []errorStatusItem
+
+const mimeProblemJSON = "application/problem+json"
@@ -0,0 +1,112 @@
+package https
+
+import (
+	"net/url"
+	"strings"
+)
+
+//return the form as a map, a single value is string and the repeated values are []string.
+//bracket keys are nested, example: user[name] is m["user"].(map[string]interface{})["name"].
+func (p *Context) FormMap() map[string]interface{} {
+	return valuesMap(p.Form())
+}
+
+func (p *Context) QueryMap() map[string]interface{} {
+	return valuesMap(p.Query())
+}
+
+func valuesMap(values url.Values) map[string]interface{} {
+	m := make(map[string]interface{})
+	for key, v := range values {
+		if len(v) == 0 {
+			continue
+		}
+
+		parts := splitKey(key)
+		node := m
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+
+		last := parts[len(parts)-1]
+		if _, ok := node[last].(map[string]interface{}); ok {
+			continue
+		}
+		if len(v) == 1 {
+			node[last] = v[0]
+		} else {
+			node[last] = v
+		}
+	}
+
+	return m
+}
+
+//rewrite bracket keys to the dotted keys of schema, example: items[0][name] to items.0.name.
+func normalizeValues(values url.Values) url.Values {
+	var normalized url.Values
+	for key := range values {
+		if strings.IndexByte(key, '[') > 0 {
+			normalized = make(url.Values, len(values))
+			break
+		}
+	}
+	if normalized == nil {
+		return values
+	}
+
+	for key, v := range values {
+		key = strings.Join(splitKey(key), ".")
+		normalized[key] = append(normalized[key], v...)
+	}
+
+	return normalized
+}
+
+//split the values of fields by comma, values is not changed.
+func splitValues(values url.Values, fields []string) url.Values {
+	split := make(url.Values, len(values))
+	for key, v := range values {
+		split[key] = v
+	}
+
+	for _, field := range fields {
+		v, ok := values[field]
+		if !ok {
+			continue
+		}
+		var items []string
+		for _, item := range v {
+			for _, s := range strings.Split(item, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					items = append(items, s)
+				}
+			}
+		}
+		split[field] = items
+	}
+
+	return split
+}
+
+//split "a[b][c]" to ["a", "b", "c"], the empty brackets of "a[]" are dropped.
+func splitKey(key string) []string {
+	pos := strings.IndexByte(key, '[')
+	if pos <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}
+	}
+
+	parts := []string{key[:pos]}
+	for _, part := range strings.Split(key[pos+1:len(key)-1], "][") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,99 @@
+package https
+
+import (
+	"errors"
+	"github.com/gorilla/schema"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newFormContext(body string) *Context {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set(headerTypeContentType, mimeURLEncodedForm)
+
+	return NewContext(httptest.NewRecorder(), r)
+}
+
+type requiredForm struct {
+	Name string `form:"name"`
+}
+
+func (f *requiredForm) Validate() error {
+	if f.Name == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+func TestReadFormEmptyValidates(t *testing.T) {
+	var f requiredForm
+	if err := newFormContext("").ReadForm(&f); err == nil {
+		t.Fatal("ReadForm of empty body should fail the validation")
+	}
+
+	if err := newFormContext("name=a").ReadForm(&f); err != nil || f.Name != "a" {
+		t.Fatalf("ReadForm = %v, %q", err, f.Name)
+	}
+}
+
+func TestReadFormBracketKeys(t *testing.T) {
+	var f struct {
+		Items []struct {
+			Name string `form:"name"`
+		} `form:"items"`
+		Tags []string `form:"tags"`
+	}
+	body := "items[0][name]=a&items[1][name]=b&tags[]=x&tags[]=y"
+	if err := newFormContext(body).ReadForm(&f); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.Items) != 2 || f.Items[0].Name != "a" || f.Items[1].Name != "b" {
+		t.Errorf("Items = %+v", f.Items)
+	}
+	if strings.Join(f.Tags, ",") != "x,y" {
+		t.Errorf("Tags = %v", f.Tags)
+	}
+}
+
+func TestReadQueryCSV(t *testing.T) {
+	type query struct {
+		IDs  []int    `url:"ids"`
+		Tags []string `url:"tags"`
+	}
+	tests := []string{
+		"/?ids=1&ids=2&tags=a,b",
+		"/?ids=1,2&tags=a,b",
+	}
+	for _, target := range tests {
+		var q query
+		p := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		if err := p.ReadQueryCSV(&q, "ids"); err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+
+		if len(q.IDs) != 2 || q.IDs[0] != 1 || q.IDs[1] != 2 {
+			t.Errorf("%s: IDs = %v", target, q.IDs)
+		}
+		if len(q.Tags) != 1 || q.Tags[0] != "a,b" {
+			t.Errorf("%s: Tags = %q, want [a,b] unsplit", target, q.Tags)
+		}
+	}
+}
+
+func TestReadFormUnknownKeys(t *testing.T) {
+	body := "name=a&_csrf=token&submit=Save"
+	var f requiredForm
+	if err := newFormContext(body).ReadForm(&f); err != nil || f.Name != "a" {
+		t.Fatalf("ReadForm = %v, %q", err, f.Name)
+	}
+
+	SetFormDecoderOptions(func(d *schema.Decoder) { d.IgnoreUnknownKeys(false) })
+	defer SetFormDecoderOptions(func(d *schema.Decoder) { d.IgnoreUnknownKeys(true) })
+	if err := newFormContext(body).ReadForm(&f); err == nil {
+		t.Error("ReadForm should fail on the unknown keys when they aren't ignored")
+	}
+}
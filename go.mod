@@ -1,5 +1,11 @@
 module github.com/mycaosf/https
 
-go 1.14
+go 1.16
 
-require github.com/gorilla/schema v1.2.0
+require (
+	github.com/gorilla/mux v1.8.0
+	github.com/gorilla/schema v1.2.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	google.golang.org/protobuf v1.28.1
+	gopkg.in/yaml.v2 v2.4.0
+)
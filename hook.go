@@ -0,0 +1,19 @@
+package https
+
+//set the hook called when the Write* and Read* methods fail, example: log the errors.
+//the hook doesn't change the error returned.
+func OnError(fn func(p *Context, err error)) {
+	errorHook = fn
+}
+
+//call the error hook if err is not nil, err is returned.
+//the error is reported by the method where it occurs, so it's reported once.
+func (p *Context) fail(err error) error {
+	if err != nil && errorHook != nil {
+		errorHook(p, err)
+	}
+
+	return err
+}
+
+var errorHook func(p *Context, err error)
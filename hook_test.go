@@ -0,0 +1,34 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type noFlushWriter struct {
+	http.ResponseWriter
+}
+
+func TestOnErrorReported(t *testing.T) {
+	var reported []error
+	OnError(func(p *Context, err error) {
+		reported = append(reported, err)
+	})
+	defer OnError(nil)
+
+	p := NewContext(noFlushWriter{httptest.NewRecorder()}, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := p.SSEvent("", "x"); err != errNotFlusher {
+		t.Fatalf("SSEvent = %v", err)
+	}
+	if _, _, err := p.FormFile("f"); err == nil {
+		t.Fatal("FormFile of a GET request should fail")
+	}
+	if err := p.UploadFile("f", nil); err == nil {
+		t.Fatal("UploadFile of a GET request should fail")
+	}
+
+	if len(reported) != 3 {
+		t.Errorf("reported %d errors, want 3: %v", len(reported), reported)
+	}
+}
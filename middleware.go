@@ -0,0 +1,66 @@
+package https
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type Handler func(p *Context)
+
+//run handlers in order, it stops if a handler calls Abort.
+func Chain(handlers ...Handler) Handler {
+	return func(p *Context) {
+		for _, h := range handlers {
+			if p.aborted {
+				break
+			}
+			h(p)
+		}
+	}
+}
+
+//adapt h to http.Handler, Close is called after h returns.
+//the Context is released after h returns, it must not be used by other goroutines then.
+func ToHTTPHandler(h Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := AcquireContext(w, r)
+		defer ReleaseContext(p)
+
+		h(p)
+		p.Close()
+	})
+}
+
+//stop the following handlers, the current handler should return after Abort.
+func (p *Context) Abort() {
+	p.aborted = true
+}
+
+func (p *Context) IsAborted() bool {
+	return p.aborted
+}
+
+//write the status code and abort.
+func (p *Context) AbortWithStatus(code int) {
+	p.WriteStatus(code)
+	p.Abort()
+}
+
+//run h with the request context of deadline d, 503 is written if the deadline exceeds
+//and h has not written the response.
+//it works only if h and its downstream calls honor the context, h is not interrupted.
+func (p *Context) WithTimeout(d time.Duration, h Handler) {
+	r := p.R
+	ctx, cancel := context.WithTimeout(r.Context(), d)
+	defer func() {
+		cancel()
+		p.R = r
+	}()
+
+	p.R = r.WithContext(ctx)
+	h(p)
+	if ctx.Err() == context.DeadlineExceeded && !p.Written() {
+		p.Error(http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,195 @@
+package https
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Offer is a response for Negotiate, Write is called if one of Types is the best for the client.
+type Offer struct {
+	Types []string
+	Write func(p *Context) error
+}
+
+func JSONOffer(v interface{}) Offer {
+	return Offer{Types: []string{mimeJSON}, Write: func(p *Context) error { return p.WriteJSON(v) }}
+}
+
+func XMLOffer(v interface{}) Offer {
+	return Offer{Types: []string{mimeXML, mimeTextXML}, Write: func(p *Context) error { return p.WriteXML(v) }}
+}
+
+func TextOffer(text string) Offer {
+	return Offer{Types: []string{mimeText}, Write: func(p *Context) error { return p.WriteText(text) }}
+}
+
+func HTMLOffer(html string) Offer {
+	return Offer{Types: []string{mimeHTML}, Write: func(p *Context) error { return p.WriteHTML(html) }}
+}
+
+//write the offer best for the Accept header of the request, example: Negotiate(JSONOffer(v), XMLOffer(v)).
+//the first offer is used when Accept is empty, it wins when the quality is same too.
+func (p *Context) Negotiate(offers ...Offer) error {
+	if len(offers) == 0 {
+		return p.fail(errUnknownDataType)
+	}
+
+	accept := p.GetHeader(headerTypeAccept)
+	if accept == "" {
+		return offers[0].Write(p)
+	}
+
+	var types []string
+	for _, offer := range offers {
+		types = append(types, offer.Types...)
+	}
+	if best := negotiate(parseAccept(accept), types); best != "" {
+		for _, offer := range offers {
+			for _, t := range offer.Types {
+				if t == best {
+					return offer.Write(p)
+				}
+			}
+		}
+	}
+
+	return p.fail(errUnknownDataType)
+}
+
+//write v as JSON, XML or text according to the Accept header of the request.
+//JSON is used when Accept is empty or */*.
+func (p *Context) NegotiateValue(v interface{}) error {
+	return p.Negotiate(JSONOffer(v), XMLOffer(v), TextOffer(fmt.Sprint(v)))
+}
+
+//return the language tags of Accept-Language sorted by q descending.
+func (p *Context) AcceptLanguages() []string {
+	var languages []string
+	for _, item := range parseAccept(p.GetHeader(headerTypeAcceptLanguage)) {
+		if item.q > 0 && item.value != "*" {
+			languages = append(languages, item.value)
+		}
+	}
+
+	return languages
+}
+
+//return the best language of supported for the client, en-US matches en and vice versa.
+//the first of supported is returned if there is no matched language.
+func (p *Context) PreferredLanguage(supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+
+	for _, item := range parseAccept(p.GetHeader(headerTypeAcceptLanguage)) {
+		if item.q <= 0 {
+			continue
+		} else if item.value == "*" {
+			return supported[0]
+		}
+		for _, v := range supported {
+			if strings.EqualFold(v, item.value) {
+				return v
+			}
+		}
+		base := languageBase(item.value)
+		for _, v := range supported {
+			if strings.EqualFold(languageBase(v), base) {
+				return v
+			}
+		}
+	}
+
+	return supported[0]
+}
+
+func languageBase(tag string) string {
+	if pos := strings.IndexAny(tag, "-_"); pos > 0 {
+		return tag[:pos]
+	}
+
+	return tag
+}
+
+type acceptItem struct {
+	value string
+	q     float64
+}
+
+//parse header like Accept, Accept-Language, the items are sorted by q descending.
+func parseAccept(header string) []acceptItem {
+	var items []acceptItem
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		value := strings.TrimSpace(params[0])
+		if value == "" {
+			continue
+		}
+
+		item := acceptItem{value: value, q: 1}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					item.q = q
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	return items
+}
+
+//return the offer with the highest quality, the first offer wins when the quality is same.
+//return "" if none is acceptable.
+func negotiate(items []acceptItem, offers []string) string {
+	var best string
+	var bestQ float64
+	for _, offer := range offers {
+		if q := acceptQuality(items, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+//the most specific matched item decides the quality of the media type.
+func acceptQuality(items []acceptItem, mediaType string) float64 {
+	q, specific := 0.0, -1
+	major := mediaType[:strings.IndexByte(mediaType, '/')+1]
+	for _, item := range items {
+		var s int
+		switch {
+		case strings.EqualFold(item.value, mediaType):
+			s = 2
+		case strings.EqualFold(item.value, major+"*"):
+			s = 1
+		case item.value == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specific {
+			q, specific = item.q, s
+		}
+	}
+
+	return q
+}
+
+const (
+	headerTypeAccept         = "Accept"
+	headerTypeAcceptLanguage = "Accept-Language"
+	mimeJSON                 = "application/json"
+	mimeXML                  = "application/xml"
+	mimeTextXML              = "text/xml"
+	mimeText                 = "text/plain"
+	mimeHTML                 = "text/html"
+)
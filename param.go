@@ -0,0 +1,40 @@
+package https
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+//set the function to read the path parameter stored by the router, mux.Vars is used by default.
+//example for chi: SetPathParamFunc(chi.URLParam)
+func SetPathParamFunc(fn func(r *http.Request, name string) string) {
+	pathParamFunc = fn
+}
+
+func (p *Context) PathParam(name string) string {
+	return pathParamFunc(p.R, name)
+}
+
+//return def when the parameter is missing or the value is not an integer
+func (p *Context) PathParamInt(name string, def int) int {
+	if v, err := strconv.Atoi(p.PathParam(name)); err == nil {
+		return v
+	}
+
+	return def
+}
+
+func (p *Context) PathParamInt64(name string, def int64) int64 {
+	if v, err := strconv.ParseInt(p.PathParam(name), 10, 64); err == nil {
+		return v
+	}
+
+	return def
+}
+
+func muxPathParam(r *http.Request, name string) string {
+	return mux.Vars(r)[name]
+}
+
+var pathParamFunc = muxPathParam
@@ -0,0 +1,27 @@
+package https
+
+import (
+	"net/http"
+	"sync"
+)
+
+//get a Context from the pool, it's the same as NewContext but reduces the allocation.
+//ReleaseContext should be called after the request is handled.
+func AcquireContext(w http.ResponseWriter, r *http.Request) *Context {
+	p := contextPool.Get().(*Context)
+	p.init(w, r)
+
+	return p
+}
+
+//put p back to the pool, p must not be used after ReleaseContext.
+func ReleaseContext(p *Context) {
+	*p = Context{}
+	contextPool.Put(p)
+}
+
+var contextPool = sync.Pool{
+	New: func() interface{} {
+		return &Context{}
+	},
+}
@@ -0,0 +1,26 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var benchContext *Context
+
+func BenchmarkNewContext(b *testing.B) {
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchContext = NewContext(w, r)
+	}
+}
+
+func BenchmarkAcquireContext(b *testing.B) {
+	w, r := httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := AcquireContext(w, r)
+		ReleaseContext(p)
+	}
+}
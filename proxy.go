@@ -0,0 +1,90 @@
+package https
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+)
+
+//set the client used by Proxy, the client should not follow the redirects so that they are passed to the client.
+//the default client doesn't follow the redirects, its CheckRedirect returns http.ErrUseLastResponse.
+func SetProxyClient(client *http.Client) {
+	proxyClient = client
+}
+
+//forward the request to target and copy the response back, 502 is written if target fails.
+//the query of the request is kept if target has no query.
+func (p *Context) Proxy(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		p.Error(http.StatusBadGateway)
+		return err
+	}
+	if u.RawQuery == "" {
+		u.RawQuery = p.R.URL.RawQuery
+	}
+
+	var body io.Reader
+	if p.R.Body != nil && p.R.Body != http.NoBody {
+		body = p.R.Body
+	}
+	req, err := http.NewRequestWithContext(p.R.Context(), p.R.Method, u.String(), body)
+	if err != nil {
+		p.Error(http.StatusBadGateway)
+		return err
+	}
+	req.ContentLength = p.R.ContentLength
+	copyHeader(req.Header, p.R.Header)
+	if ip := remoteIP(p.R.RemoteAddr); ip != "" {
+		if prior := p.R.Header.Get(headerTypeForwardedFor); prior != "" {
+			ip = prior + ", " + ip
+		}
+		req.Header.Set(headerTypeForwardedFor, ip)
+	}
+
+	client := proxyClient
+	if client == nil {
+		client = defaultProxyClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		p.Error(http.StatusBadGateway)
+		return err
+	}
+	defer resp.Body.Close()
+
+	copyHeader(p.W.Header(), resp.Header)
+	p.W.WriteHeader(resp.StatusCode)
+	_, err = io.Copy(p.W, resp.Body)
+
+	return err
+}
+
+//copy the headers except the hop-by-hop headers.
+func copyHeader(to, from http.Header) {
+	for k, v := range from {
+		if !hopHeaders[k] {
+			to[k] = append([]string(nil), v...)
+		}
+	}
+}
+
+var (
+	proxyClient        *http.Client
+	defaultProxyClient = &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	hopHeaders = map[string]bool{
+		"Connection":          true,
+		"Keep-Alive":          true,
+		"Proxy-Authenticate":  true,
+		"Proxy-Authorization": true,
+		"Proxy-Connection":    true,
+		"Te":                  true,
+		"Trailer":             true,
+		"Transfer-Encoding":   true,
+		"Upgrade":             true,
+	}
+)
@@ -0,0 +1,28 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxyRedirect(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		w.Write([]byte("new"))
+	}))
+	defer backend.Close()
+
+	w := httptest.NewRecorder()
+	p := NewContext(w, httptest.NewRequest(http.MethodGet, "/old", nil))
+	if err := p.Proxy(backend.URL + "/old"); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusFound || w.Header().Get(headerTypeLocation) != "/new" {
+		t.Errorf("code = %d, Location = %q, want the redirect of the backend", w.Code, w.Header().Get(headerTypeLocation))
+	}
+}
@@ -0,0 +1,97 @@
+package https
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//serve content of size bytes, a single range of Range header is supported.
+//206 is written for the range request, 416 for the multi-range or unsatisfiable request.
+//the range of unknown unit is ignored, the whole content is served.
+func (p *Context) ServeReader(content io.ReadSeeker, size int64, contentType string) error {
+	h := p.W.Header()
+	h.Set(headerTypeAcceptRanges, "bytes")
+	if contentType != "" {
+		h.Set(headerTypeContentType, contentType)
+	}
+
+	start, length, code := int64(0), size, http.StatusOK
+	if header := p.GetHeader(headerTypeRange); strings.HasPrefix(header, rangePrefix) {
+		var err error
+		if start, length, err = parseRange(header, size); err != nil {
+			h.Set(headerTypeContentRange, fmt.Sprintf("bytes */%d", size))
+			p.Error(http.StatusRequestedRangeNotSatisfiable)
+			return err
+		}
+		code = http.StatusPartialContent
+		h.Set(headerTypeContentRange, fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size))
+	}
+
+	if _, err := content.Seek(start, io.SeekStart); err != nil {
+		p.Error(http.StatusInternalServerError)
+		return err
+	}
+	h.Set(headerTypeContentLength, strconv.FormatInt(length, 10))
+	p.W.WriteHeader(code)
+	_, err := io.CopyN(p.W, content, length)
+
+	return err
+}
+
+//parse "bytes=start-end", "bytes=start-" or "bytes=-suffix".
+func parseRange(header string, size int64) (start, length int64, err error) {
+	if !strings.HasPrefix(header, rangePrefix) {
+		return 0, 0, errRange
+	}
+	spec := strings.TrimSpace(header[len(rangePrefix):])
+	if strings.Contains(spec, ",") {
+		return 0, 0, errMultiRange
+	}
+	pos := strings.IndexByte(spec, '-')
+	if pos < 0 {
+		return 0, 0, errRange
+	}
+
+	first, last := strings.TrimSpace(spec[:pos]), strings.TrimSpace(spec[pos+1:])
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, errRange
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, nil
+	}
+
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil || start < 0 || start >= size {
+		return 0, 0, errRange
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, 0, errRange
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+
+	return start, end - start + 1, nil
+}
+
+var (
+	errRange      = errors.New("Error range")
+	errMultiRange = errors.New("Multiple ranges not supported")
+)
+
+const (
+	headerTypeAcceptRanges = "Accept-Ranges"
+	headerTypeContentRange = "Content-Range"
+	headerTypeRange        = "Range"
+	rangePrefix            = "bytes="
+)
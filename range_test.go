@@ -0,0 +1,37 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeReaderRange(t *testing.T) {
+	tests := []struct {
+		header string
+		code   int
+		body   string
+	}{
+		{"", http.StatusOK, "0123456789"},
+		{"bytes=2-4", http.StatusPartialContent, "234"},
+		{"bytes=-3", http.StatusPartialContent, "789"},
+		{"items=0-1", http.StatusOK, "0123456789"},
+		{"bytes=20-", http.StatusRequestedRangeNotSatisfiable, ""},
+		{"bytes=0-1,3-4", http.StatusRequestedRangeNotSatisfiable, ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			r.Header.Set(headerTypeRange, tt.header)
+		}
+		w := httptest.NewRecorder()
+		NewContext(w, r).ServeReader(strings.NewReader("0123456789"), 10, mimeText)
+
+		if w.Code != tt.code {
+			t.Errorf("%q: code = %d, want %d", tt.header, w.Code, tt.code)
+		} else if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%q: body = %q, want %q", tt.header, w.Body.String(), tt.body)
+		}
+	}
+}
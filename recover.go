@@ -0,0 +1,31 @@
+package https
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+//recover the panic of the handler, it must be deferred directly: defer p.Recover().
+//the panic is logged, and 500 is written if the response has not started.
+//onPanic is called instead of writing 500 if it's set.
+//http.ErrAbortHandler is panicked again, so that the server aborts the response.
+func (p *Context) Recover(onPanic ...func(p *Context, err interface{})) {
+	err := recover()
+	if err == nil {
+		return
+	} else if err == http.ErrAbortHandler {
+		panic(err)
+	}
+
+	log.Printf("https: panic serving %s %s: %v\n%s", p.R.Method, p.R.URL, err, debug.Stack())
+	if p.Written() {
+		return
+	}
+	p.ResetResponse()
+	if len(onPanic) > 0 && onPanic[0] != nil {
+		onPanic[0](p, err)
+	} else {
+		p.Error(http.StatusInternalServerError)
+	}
+}
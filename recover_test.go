@@ -0,0 +1,31 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	w := httptest.NewRecorder()
+	func() {
+		p := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		defer p.Recover()
+		panic("boom")
+	}()
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("code = %d, want 500", w.Code)
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	defer func() {
+		if err := recover(); err != http.ErrAbortHandler {
+			t.Errorf("recover = %v, want http.ErrAbortHandler", err)
+		}
+	}()
+
+	p := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	defer p.Recover()
+	panic(http.ErrAbortHandler)
+}
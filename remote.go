@@ -0,0 +1,151 @@
+package https
+
+import (
+	"crypto/x509"
+	"net"
+	"strings"
+)
+
+//proxies may be IP or CIDR, example: "10.0.0.1", "192.168.0.0/16".
+//the proxy headers are honored only when the request comes from a trusted proxy.
+func SetTrustedProxies(proxies ...string) error {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if strings.Contains(proxy, ":") {
+				proxy += "/128"
+			} else {
+				proxy += "/32"
+			}
+		}
+		_, n, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return err
+		}
+		nets = append(nets, n)
+	}
+	trustedProxies = nets
+
+	return nil
+}
+
+//return the client IP, X-Forwarded-For and X-Real-IP are used if the request comes from a trusted proxy.
+//X-Forwarded-For is walked from the right, the first address which isn't a trusted proxy is the client,
+//so that the addresses sent by the client can't spoof it.
+func (p *Context) ClientIP() string {
+	remote := remoteIP(p.R.RemoteAddr)
+	if !isTrustedProxy(remote) {
+		return remote
+	}
+
+	if forwarded := p.GetHeader(headerTypeForwardedFor); forwarded != "" {
+		var last string
+		list := strings.Split(forwarded, ",")
+		for i := len(list) - 1; i >= 0; i-- {
+			v := strings.TrimSpace(list[i])
+			if net.ParseIP(v) == nil {
+				break
+			} else if !isTrustedProxy(v) {
+				return v
+			}
+			last = v
+		}
+		if last != "" {
+			return last
+		}
+	}
+	if ip := strings.TrimSpace(p.GetHeader(headerTypeRealIP)); net.ParseIP(ip) != nil {
+		return ip
+	}
+
+	return remote
+}
+
+//return "https" or "http", X-Forwarded-Proto is used if the request comes from a trusted proxy.
+func (p *Context) Scheme() string {
+	if p.fromTrustedProxy() {
+		if proto := firstHeaderValue(p.GetHeader(headerTypeForwardedProto)); proto != "" {
+			return strings.ToLower(proto)
+		}
+	}
+	if p.R.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+//X-Forwarded-Host is used if the request comes from a trusted proxy.
+func (p *Context) Host() string {
+	if p.fromTrustedProxy() {
+		if host := firstHeaderValue(p.GetHeader(headerTypeForwardedHost)); host != "" {
+			return host
+		}
+	}
+
+	return p.R.Host
+}
+
+//return the absolute URL of the request.
+func (p *Context) FullURL() string {
+	return p.Scheme() + "://" + p.Host() + p.R.URL.RequestURI()
+}
+
+func (p *Context) fromTrustedProxy() bool {
+	return isTrustedProxy(remoteIP(p.R.RemoteAddr))
+}
+
+func firstHeaderValue(value string) string {
+	if pos := strings.IndexByte(value, ','); pos >= 0 {
+		value = value[:pos]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+//return the certificate of the client, ok is false if the connection isn't mutual TLS.
+func (p *Context) ClientCertificate() (cert *x509.Certificate, ok bool) {
+	if p.R.TLS == nil || len(p.R.TLS.PeerCertificates) == 0 {
+		return nil, false
+	}
+
+	return p.R.TLS.PeerCertificates[0], true
+}
+
+//return the common name of the client certificate, "" if there is no certificate.
+func (p *Context) ClientCertCommonName() string {
+	if cert, ok := p.ClientCertificate(); ok {
+		return cert.Subject.CommonName
+	}
+
+	return ""
+}
+
+func remoteIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+func isTrustedProxy(addr string) bool {
+	if ip := net.ParseIP(addr); ip != nil {
+		for _, n := range trustedProxies {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+var trustedProxies []*net.IPNet
+
+const (
+	headerTypeForwardedFor   = "X-Forwarded-For"
+	headerTypeRealIP         = "X-Real-IP"
+	headerTypeForwardedProto = "X-Forwarded-Proto"
+	headerTypeForwardedHost  = "X-Forwarded-Host"
+)
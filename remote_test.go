@@ -0,0 +1,38 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	if err := SetTrustedProxies("10.0.0.0/8"); err != nil {
+		t.Fatal(err)
+	}
+	defer SetTrustedProxies()
+
+	tests := []struct {
+		remote    string
+		forwarded string
+		want      string
+	}{
+		{"8.8.4.4:1234", "6.6.6.6", "8.8.4.4"},
+		{"10.0.0.1:1234", "", "10.0.0.1"},
+		{"10.0.0.1:1234", "8.8.8.8", "8.8.8.8"},
+		{"10.0.0.1:1234", "6.6.6.6, 8.8.8.8", "8.8.8.8"},
+		{"10.0.0.1:1234", "6.6.6.6, 8.8.8.8, 10.0.0.2", "8.8.8.8"},
+		{"10.0.0.1:1234", "bad, 10.0.0.2", "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remote
+		if tt.forwarded != "" {
+			r.Header.Set(headerTypeForwardedFor, tt.forwarded)
+		}
+
+		if got := NewContext(httptest.NewRecorder(), r).ClientIP(); got != tt.want {
+			t.Errorf("%s %q: ClientIP = %s, want %s", tt.remote, tt.forwarded, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package https
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"html/template"
+	"io"
+	"net/http"
+)
+
+//Render writes a response, custom responses like CSV or PDF can be passed to Context.Render too.
+type Render interface {
+	Render(w http.ResponseWriter) error
+}
+
+//write the response by r, the error is reported by the error hook.
+func (p *Context) Render(r Render) error {
+	return p.fail(r.Render(p.W))
+}
+
+//JSON renders Data as JSON, the HTML characters are escaped unless it's disabled by SetJSONHTMLEscape.
+type JSON struct {
+	Data interface{}
+}
+
+func (r JSON) Render(w http.ResponseWriter) error {
+	data, err := json.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	escapeJSON(&buf, data)
+	w.Header().Set(headerTypeContentType, headerTypeContentJSON)
+	_, err = buf.WriteTo(w)
+
+	return err
+}
+
+type XML struct {
+	Data interface{}
+}
+
+func (r XML) Render(w http.ResponseWriter) error {
+	data, err := xml.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set(headerTypeContentType, headerTypeContentXML)
+	_, err = w.Write(data)
+
+	return err
+}
+
+//Text renders Data as text/plain verbatim.
+type Text struct {
+	Data string
+}
+
+func (r Text) Render(w http.ResponseWriter) error {
+	w.Header().Set(headerTypeContentType, headerTypeContentText)
+	_, err := io.WriteString(w, r.Data)
+
+	return err
+}
+
+//HTML renders Data as text/html verbatim, it isn't escaped.
+type HTML struct {
+	Data string
+}
+
+func (r HTML) Render(w http.ResponseWriter) error {
+	w.Header().Set(headerTypeContentType, headerTypeContentHTML)
+	_, err := io.WriteString(w, r.Data)
+
+	return err
+}
+
+//Redirect redirects to Location, Code should be 3xx.
+//Request is needed to resolve the relative Location.
+type Redirect struct {
+	Request  *http.Request
+	Location string
+	Code     int
+}
+
+func (r Redirect) Render(w http.ResponseWriter) error {
+	if r.Code < 300 || r.Code > 399 {
+		return errRedirectCode
+	}
+	http.Redirect(w, r.Request, r.Location, r.Code)
+
+	return nil
+}
+
+//Template executes the template Name of Template, Template itself is executed if Name is empty.
+//the output is buffered, nothing is written if the execution fails.
+type Template struct {
+	Template *template.Template
+	Name     string
+	Data     interface{}
+}
+
+func (r Template) Render(w http.ResponseWriter) error {
+	var buf bytes.Buffer
+	var err error
+	if r.Name == "" {
+		err = r.Template.Execute(&buf, r.Data)
+	} else {
+		err = r.Template.ExecuteTemplate(&buf, r.Name, r.Data)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set(headerTypeContentType, headerTypeContentHTML)
+	_, err = buf.WriteTo(w)
+
+	return err
+}
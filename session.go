@@ -0,0 +1,108 @@
+package https
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+//Session is a small key/value map stored in a signed cookie, the values are encoded as JSON.
+type Session struct {
+	ctx    *Context
+	values map[string]interface{}
+}
+
+//set the key to sign the session cookie, it must be set before using Session.
+func SetSessionKey(key []byte) {
+	sessionKey = key
+}
+
+//return the session of the request, an empty session is returned if the cookie is missing or tampered.
+func (p *Context) Session() *Session {
+	if p.session != nil {
+		return p.session
+	}
+
+	p.session = &Session{ctx: p, values: make(map[string]interface{})}
+	if len(sessionKey) > 0 {
+		if data, err := p.GetSignedCookie(sessionCookieName, sessionKey); err == nil {
+			json.Unmarshal([]byte(data), &p.session.values)
+		}
+	}
+
+	return p.session
+}
+
+func (p *Session) Get(key string) (value interface{}, ok bool) {
+	value, ok = p.values[key]
+
+	return
+}
+
+func (p *Session) Set(key string, value interface{}) {
+	p.values[key] = value
+}
+
+func (p *Session) Delete(key string) {
+	delete(p.values, key)
+}
+
+//write the session cookie, it must be called before writing the response.
+func (p *Session) Save() error {
+	if len(sessionKey) == 0 {
+		return errNoSessionKey
+	}
+
+	data, err := json.Marshal(p.values)
+	if err == nil {
+		p.ctx.SetSignedCookie(sessionCookieName, string(data), sessionKey)
+	}
+
+	return err
+}
+
+//add a one-shot message to the session, it's returned by Flashes of the next request.
+func (p *Context) AddFlash(message string) error {
+	session := p.Session()
+	session.Set(sessionFlashKey, append(session.flashes(), message))
+
+	return session.Save()
+}
+
+//return the flash messages and clear them.
+func (p *Context) Flashes() []string {
+	session := p.Session()
+	flashes := session.flashes()
+	if len(flashes) > 0 {
+		session.Delete(sessionFlashKey)
+		session.Save()
+	}
+
+	return flashes
+}
+
+func (p *Session) flashes() []string {
+	switch v := p.values[sessionFlashKey].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		flashes := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				flashes = append(flashes, s)
+			}
+		}
+		return flashes
+	}
+
+	return nil
+}
+
+var (
+	sessionKey      []byte
+	errNoSessionKey = errors.New("No session key")
+)
+
+const (
+	sessionCookieName = "_session"
+	sessionFlashKey   = "_flash"
+)
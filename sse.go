@@ -0,0 +1,75 @@
+package https
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//set the headers of server-sent events, it should be called before SSEvent.
+func (p *Context) StartSSE() {
+	h := p.W.Header()
+	h.Set(headerTypeContentType, headerTypeContentEventStream)
+	h.Set(headerTypeCacheControl, "no-cache")
+	h.Set(headerTypeConnection, "keep-alive")
+}
+
+//write a server-sent event and flush it.
+//data of string or []byte is written directly, others are encoded as JSON.
+//data is split into lines by \r\n, \r or \n, the event containing \r or \n is rejected.
+func (p *Context) SSEvent(event string, data interface{}) error {
+	if _, ok := getFlusher(p.W); !ok {
+		return p.fail(errNotFlusher)
+	} else if strings.ContainsAny(event, "\r\n") {
+		return p.fail(errSSEvent)
+	}
+
+	var text string
+	switch v := data.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		d, err := json.Marshal(v)
+		if err != nil {
+			return p.fail(err)
+		}
+		text = string(d)
+	}
+
+	if p.W.Header().Get(headerTypeContentType) == "" {
+		p.SetHeader(headerTypeContentType, headerTypeContentEventStream)
+	}
+
+	p.sseID++
+	var buf bytes.Buffer
+	buf.WriteString("id: " + strconv.FormatUint(p.sseID, 10) + "\n")
+	if event != "" {
+		buf.WriteString("event: " + event + "\n")
+	}
+	for _, line := range strings.Split(sseNewline.Replace(text), "\n") {
+		buf.WriteString("data: " + line + "\n")
+	}
+	buf.WriteByte('\n')
+
+	_, err := buf.WriteTo(p.W)
+	if err == nil {
+		err = p.Flush()
+	}
+
+	return p.fail(err)
+}
+
+var (
+	sseNewline = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+	errSSEvent = errors.New("Error event name")
+)
+
+const (
+	headerTypeCacheControl       = "Cache-Control"
+	headerTypeConnection         = "Connection"
+	headerTypeContentEventStream = "text/event-stream"
+)
@@ -0,0 +1,36 @@
+package https
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSSEvent(t *testing.T) {
+	tests := []struct {
+		event string
+		data  string
+		want  string
+	}{
+		{"", "a", "id: 1\ndata: a\n\n"},
+		{"msg", "a\nb", "id: 1\nevent: msg\ndata: a\ndata: b\n\n"},
+		{"", "a\rb\r\nc", "id: 1\ndata: a\ndata: b\ndata: c\n\n"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		p := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if err := p.SSEvent(tt.event, tt.data); err != nil {
+			t.Fatal(err)
+		}
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("SSEvent(%q, %q) = %q, want %q", tt.event, tt.data, got, tt.want)
+		}
+	}
+
+	for _, event := range []string{"x\ndata: evil", "x\rdata: evil"} {
+		w := httptest.NewRecorder()
+		if err := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil)).SSEvent(event, "a"); err != errSSEvent || w.Body.Len() != 0 {
+			t.Errorf("SSEvent(%q) = %v, body %q", event, err, w.Body.String())
+		}
+	}
+}
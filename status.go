@@ -0,0 +1,55 @@
+package https
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//write 200 and v as JSON
+func (p *Context) OK(v interface{}) error {
+	return p.writeJSONCode(http.StatusOK, v)
+}
+
+//write 201 and v as JSON
+func (p *Context) Created(v interface{}) error {
+	return p.writeJSONCode(http.StatusCreated, v)
+}
+
+//write 202 and v as JSON
+func (p *Context) Accepted(v interface{}) error {
+	return p.writeJSONCode(http.StatusAccepted, v)
+}
+
+func (p *Context) NoContent() {
+	p.WriteStatus(http.StatusNoContent)
+}
+
+//write 400 with msg, the status text is used if msg is empty.
+func (p *Context) BadRequest(msg string) {
+	if msg == "" {
+		p.Error(http.StatusBadRequest)
+	} else {
+		http.Error(p.W, msg, http.StatusBadRequest)
+	}
+}
+
+func (p *Context) Unauthorized() {
+	p.Error(http.StatusUnauthorized)
+}
+
+func (p *Context) Forbidden() {
+	p.Error(http.StatusForbidden)
+}
+
+//v is encoded before writing the status code, so nothing is written if it fails.
+func (p *Context) writeJSONCode(code int, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return p.fail(err)
+	}
+
+	p.SetHeader(headerTypeContentType, headerTypeContentJSON)
+	p.W.WriteHeader(code)
+
+	return p.WriteDataJSON(data)
+}
@@ -0,0 +1,88 @@
+package https
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+//set JSON content type and return an encoder writing to the response directly,
+//so that the large data can be encoded item by item without buffering.
+func (p *Context) StreamJSON() *json.Encoder {
+	p.SetHeader(headerTypeContentType, headerTypeContentJSON)
+
+	encoder := json.NewEncoder(p.W)
+	encoder.SetEscapeHTML(jsonHTMLEscape)
+
+	return encoder
+}
+
+//the channel is closed when the client closes the connection, or the request is canceled.
+//Stream stops by the same signal.
+func (p *Context) ClientGone() <-chan struct{} {
+	return p.R.Context().Done()
+}
+
+func (p *Context) IsClientGone() bool {
+	select {
+	case <-p.ClientGone():
+		return true
+	default:
+		return false
+	}
+}
+
+//call step repeatedly and flush after each call, until step returns false or the client is gone.
+//return true if the client is gone, see ClientGone.
+func (p *Context) Stream(step func(w io.Writer) bool) bool {
+	done := p.ClientGone()
+	for {
+		select {
+		case <-done:
+			return true
+		default:
+			keepOpen := step(p.W)
+			p.Flush()
+			if !keepOpen {
+				return false
+			}
+		}
+	}
+}
+
+//write the items received from ch as a JSON array, each item is flushed as it arrives.
+//the array is closed when ch is closed, the error of the request context is returned if the client is gone.
+func (p *Context) StreamJSONFromChannel(ch <-chan interface{}) error {
+	p.SetHeader(headerTypeContentType, headerTypeContentJSON)
+	if _, err := io.WriteString(p.W, "["); err != nil {
+		return p.fail(err)
+	}
+	p.Flush()
+
+	done := p.ClientGone()
+	for first := true; ; first = false {
+		select {
+		case <-done:
+			return p.R.Context().Err()
+		case v, ok := <-ch:
+			if !ok {
+				_, err := io.WriteString(p.W, "]")
+				return p.fail(err)
+			}
+
+			data, err := json.Marshal(v)
+			if err != nil {
+				return p.fail(err)
+			}
+			var buf bytes.Buffer
+			if !first {
+				buf.WriteByte(',')
+			}
+			escapeJSON(&buf, data)
+			if _, err = buf.WriteTo(p.W); err != nil {
+				return p.fail(err)
+			}
+			p.Flush()
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package https
+
+import (
+	"errors"
+	"html/template"
+)
+
+//set the templates used by ExecuteTemplate.
+func SetTemplates(t *template.Template) {
+	templates = t
+}
+
+//execute the template name of tmpl, tmpl itself is executed if name is empty.
+//the output is buffered, nothing is written if the execution fails.
+func (p *Context) RenderTemplate(tmpl *template.Template, name string, data interface{}) error {
+	return p.Render(Template{Template: tmpl, Name: name, Data: data})
+}
+
+//execute the template name set by SetTemplates.
+func (p *Context) ExecuteTemplate(name string, data interface{}) error {
+	if templates == nil {
+		return errNoTemplates
+	}
+
+	return p.RenderTemplate(templates, name, data)
+}
+
+var (
+	templates      *template.Template
+	errNoTemplates = errors.New("No templates")
+)
@@ -0,0 +1,27 @@
+package https
+
+//Validator is called by ReadForm, ReadJSON and ReadXML after decoding.
+type Validator interface {
+	Validate() error
+}
+
+//set the global validator, it's called before Validator.Validate.
+//example: SetValidator(validator.New().Struct)
+func SetValidator(fn func(interface{}) error) {
+	globalValidator = fn
+}
+
+func validate(v interface{}) error {
+	if globalValidator != nil {
+		if err := globalValidator(v); err != nil {
+			return err
+		}
+	}
+	if validator, ok := v.(Validator); ok {
+		return validator.Validate()
+	}
+
+	return nil
+}
+
+var globalValidator func(interface{}) error
@@ -0,0 +1,278 @@
+package https
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+)
+
+//responseWriter records the status code and the size of the response.
+//it always implements http.Flusher, http.Hijacker, http.Pusher and io.ReaderFrom, they are delegated to the wrapped
+//ResponseWriter: Flush does nothing, Hijack and Push fail if it doesn't support them.
+//so p.W.(http.Flusher) doesn't tell whether flushing works, use Context.Flush, Context.Hijack and Context.Push,
+//or Unwrap to check the wrapped ResponseWriter.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+//the superfluous call is ignored.
+func (p *responseWriter) WriteHeader(code int) {
+	if p.status == 0 {
+		p.status = code
+		p.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (p *responseWriter) Write(data []byte) (int, error) {
+	if p.status == 0 {
+		p.status = http.StatusOK
+	}
+	n, err := p.ResponseWriter.Write(data)
+	p.size += int64(n)
+
+	return n, err
+}
+
+//do nothing if the ResponseWriter doesn't support Flush.
+func (p *responseWriter) Flush() {
+	if f, ok := p.ResponseWriter.(http.Flusher); ok {
+		if p.status == 0 {
+			p.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+func (p *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := p.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, errNotHijacker
+}
+
+func (p *responseWriter) Push(target string, opts *http.PushOptions) error {
+	return push(p.ResponseWriter, target, opts)
+}
+
+//io.Copy to the response uses ReadFrom of the wrapped ResponseWriter, example: sendfile of the file.
+func (p *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	if p.status == 0 {
+		p.status = http.StatusOK
+	}
+	var n int64
+	var err error
+	if rf, ok := p.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(p.ResponseWriter, r)
+	}
+	p.size += n
+
+	return n, err
+}
+
+func (p *responseWriter) Unwrap() http.ResponseWriter {
+	return p.ResponseWriter
+}
+
+//send the buffered data to the client.
+//the headers are committed, they can't be changed after Flush.
+func (p *Context) Flush() error {
+	flusher, ok := getFlusher(p.W)
+	if !ok {
+		return errNotFlusher
+	}
+	flusher.Flush()
+
+	return nil
+}
+
+//take over the connection, example: upgrade to WebSocket.
+//the Context should not be used to write the response after Hijack.
+//the wrapped ResponseWriter is hijacked if p.W is compressed or buffered.
+func (p *Context) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w := p.W
+	for {
+		if h, ok := w.(http.Hijacker); ok {
+			return h.Hijack()
+		}
+		u, wrapped := w.(interface{ Unwrap() http.ResponseWriter })
+		if !wrapped {
+			return nil, nil, errNotHijacker
+		}
+		w = u.Unwrap()
+	}
+}
+
+//ok is true only if all the wrapped ResponseWriter support Flush.
+func getFlusher(w http.ResponseWriter) (http.Flusher, bool) {
+	flusher, ok := w.(http.Flusher)
+	for ok {
+		u, wrapped := w.(interface{ Unwrap() http.ResponseWriter })
+		if !wrapped {
+			break
+		}
+		w = u.Unwrap()
+		_, ok = w.(http.Flusher)
+	}
+
+	return flusher, ok
+}
+
+//push the target to the client by HTTP/2 server push, opts may be nil.
+//errNotPusher is returned if the connection doesn't support push, example: HTTP/1.x.
+func (p *Context) Push(target string, opts *http.PushOptions) error {
+	return push(p.W, target, opts)
+}
+
+//push by the first http.Pusher of the wrapped ResponseWriter.
+func push(w http.ResponseWriter, target string, opts *http.PushOptions) error {
+	for {
+		if pusher, ok := w.(http.Pusher); ok {
+			return pusher.Push(target, opts)
+		}
+		u, wrapped := w.(interface{ Unwrap() http.ResponseWriter })
+		if !wrapped {
+			return errNotPusher
+		}
+		w = u.Unwrap()
+	}
+}
+
+//bufferWriter keeps the status code and the body in memory until commit.
+type bufferWriter struct {
+	http.ResponseWriter
+	buf       bytes.Buffer
+	code      int
+	max       int
+	committed bool
+}
+
+func (p *bufferWriter) WriteHeader(code int) {
+	if p.committed {
+		p.ResponseWriter.WriteHeader(code)
+	} else if p.code == 0 {
+		p.code = code
+	}
+}
+
+func (p *bufferWriter) Write(data []byte) (int, error) {
+	if p.committed {
+		return p.ResponseWriter.Write(data)
+	} else if p.max > 0 && p.buf.Len()+len(data) > p.max {
+		return 0, errResponseTooLarge
+	}
+
+	return p.buf.Write(data)
+}
+
+func (p *bufferWriter) Flush() {
+	p.commit()
+	if f, ok := p.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (p *bufferWriter) Unwrap() http.ResponseWriter {
+	return p.ResponseWriter
+}
+
+func (p *bufferWriter) commit() error {
+	if p.committed {
+		return nil
+	}
+
+	p.committed = true
+	if p.code != 0 {
+		p.ResponseWriter.WriteHeader(p.code)
+	}
+	_, err := p.buf.WriteTo(p.ResponseWriter)
+
+	return err
+}
+
+//keep the status code and the body in memory until Commit, Flush or Close,
+//so that the handler can discard the response by ResetResponse and write another one.
+//the whole body is kept in memory, Write fails if it's larger than maxSize, 0 means unlimited.
+func (p *Context) BufferResponse(maxSize int) {
+	if _, ok := p.W.(*bufferWriter); !ok {
+		p.W = &bufferWriter{ResponseWriter: p.W, max: maxSize}
+	}
+}
+
+//write the buffered response, the following data is written directly.
+func (p *Context) Commit() error {
+	if w, ok := p.W.(*bufferWriter); ok {
+		return w.commit()
+	}
+
+	return nil
+}
+
+//discard the buffered status code and body, the headers are not changed.
+//return false if the response isn't buffered or has been committed.
+func (p *Context) ResetResponse() bool {
+	if w, ok := p.W.(*bufferWriter); ok && !w.committed {
+		w.buf.Reset()
+		w.code = 0
+		return true
+	}
+
+	return false
+}
+
+//finish the response, it writes the buffered data and flushes the compressed data.
+func (p *Context) Close() error {
+	var err error
+	for {
+		switch w := p.W.(type) {
+		case *gzipWriter:
+			p.W = w.ResponseWriter
+			if e := w.Close(); err == nil {
+				err = e
+			}
+		case *bufferWriter:
+			p.W = w.ResponseWriter
+			if e := w.commit(); err == nil {
+				err = e
+			}
+		default:
+			return err
+		}
+	}
+}
+
+//return the status code written, 200 if it's not written.
+func (p *Context) Status() int {
+	if p.rw.status == 0 {
+		return http.StatusOK
+	}
+
+	return p.rw.status
+}
+
+//return true if the status code or the body has been written.
+//the buffered response is not written until it's committed, see BufferResponse.
+func (p *Context) Written() bool {
+	if w, ok := p.W.(*gzipWriter); ok && (w.code != 0 || len(w.buf) > 0) {
+		return true
+	}
+
+	return p.rw.status != 0
+}
+
+//return the size of the response body written.
+func (p *Context) BytesWritten() int64 {
+	return p.rw.size
+}
+
+var (
+	errResponseTooLarge = errors.New("Response too large")
+	errNotPusher        = errors.New("Push not supported")
+)
@@ -0,0 +1,52 @@
+package https
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (w *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+
+	return nil, nil, nil
+}
+
+func TestHijackWrapped(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(headerTypeAcceptEncoding, "gzip")
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	p := NewContext(w, r)
+	p.EnableCompression()
+	p.BufferResponse(0)
+
+	if _, _, err := p.Hijack(); err != nil || !w.hijacked {
+		t.Fatalf("Hijack = %v, hijacked = %v", err, w.hijacked)
+	}
+}
+
+func TestResponseWriterDelegation(t *testing.T) {
+	p := NewContext(noFlushWriter{httptest.NewRecorder()}, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := p.Flush(); err != errNotFlusher {
+		t.Errorf("Flush = %v, want errNotFlusher", err)
+	}
+	if err := p.Push("/a.css", nil); err != errNotPusher {
+		t.Errorf("Push = %v, want errNotPusher", err)
+	}
+	if _, _, err := p.Hijack(); err != errNotHijacker {
+		t.Errorf("Hijack = %v, want errNotHijacker", err)
+	}
+
+	if n, err := io.Copy(p.W, strings.NewReader("hello")); err != nil || n != 5 || p.BytesWritten() != 5 || p.Status() != http.StatusOK {
+		t.Errorf("io.Copy = %d, %v, BytesWritten = %d", n, err, p.BytesWritten())
+	}
+}